@@ -0,0 +1,233 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/drieschel/yddns/internal/client"
+	"github.com/drieschel/yddns/internal/config"
+	"github.com/drieschel/yddns/internal/resolver"
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/pelletier/go-toml/v2"
+	"github.com/spf13/cobra"
+	"go.yaml.in/yaml/v3"
+)
+
+const (
+	flagLookup = "lookup"
+	flagReveal = "reveal"
+
+	keyConfFiles          = "conf_files"
+	keyError              = "error"
+	keyFile               = "file"
+	keyResolvedRefreshUrl = "resolved_refresh_url"
+	keySource             = "source"
+	keyTemplate           = "template"
+	keyTemplateSource     = "template_source"
+
+	maskedSecret = "********"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect and validate the config file",
+	Long:  `Inspect and validate the config file`,
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the effective config",
+	Long:  `Show the effective config after applying defaults and templates including template sources and resolved refresh urls`,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		lookup, _ := cmd.Flags().GetBool(flagLookup)
+		output, _ := cmd.Flags().GetString(flagOutput)
+		reveal, _ := cmd.Flags().GetBool(flagReveal)
+
+		cfg, err := config.NewFileConfig(version)
+		if err != nil {
+			exitWithError(err)
+		}
+
+		client := client.NewClient(cfg.CreateFileCache(), cfg.CreateFileHistory(), resolver.NewDnsResolverWithDefaultValues(), &http.Client{})
+		client.SetWarningHandler(func(domain *config.Domain, warning error) { logWarning(warning) })
+
+		err = printConfig(os.Stdout, createEffectiveConfig(cfg, client, lookup, reveal), output)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Validate the config file",
+	Long:  `Validate the config file including unknown keys, supported values, templates and refresh url placeholders`,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		files, errs := config.ValidateFileConfig(version)
+		var notFoundErr *config.NotFoundError
+		if len(errs) == 1 && errors.As(errs[0], &notFoundErr) {
+			exitWithError(errs[0])
+		}
+
+		for _, err := range errs {
+			fmt.Fprintln(os.Stderr, err)
+		}
+
+		name := "config"
+		if len(files) > 0 {
+			name = fmt.Sprintf("config %s", strings.Join(files, ", "))
+		}
+
+		if len(errs) > 0 {
+			fmt.Fprintf(os.Stderr, "%s has %d problem(s)\n", name, len(errs))
+			os.Exit(getExitCode(errs[0]))
+		}
+
+		fmt.Printf("%s is valid\n", name)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configShowCmd)
+	configCmd.AddCommand(configValidateCmd)
+	configCmd.PersistentFlags().StringP(flagConfigFile, "c", "", "Override default config using absolute file path")
+
+	configShowCmd.Flags().StringP(flagOutput, "o", outputToml, fmt.Sprintf("Set output format [%s|%s|%s]", outputToml, outputJson, outputYaml))
+	configShowCmd.Flags().Bool(flagLookup, false, "Determine ip addresses to resolve the ip placeholders of the refresh urls")
+	configShowCmd.Flags().Bool(flagReveal, false, "Show secrets instead of masking them")
+	configShowCmd.Flags().SortFlags = false
+}
+
+func createEffectiveConfig(cfg *config.Config, client *client.Client, lookup bool, reveal bool) map[string]any {
+	domains := []map[string]any{}
+	for _, domain := range cfg.Domains {
+		domains = append(domains, createEffectiveDomain(cfg, client, domain, lookup, reveal))
+	}
+
+	templates := map[string]any{}
+	for templateName, template := range cfg.Templates {
+		values := map[string]any{}
+		_ = mapstructure.Decode(template, &values)
+		values[keySource] = cfg.TemplateSources[templateName]
+		templates[templateName] = values
+	}
+
+	return map[string]any{
+		keyFile:                              cfg.File,
+		keyConfFiles:                         cfg.ConfFiles,
+		config.KeyCacheCreatedExpirySeconds:  cfg.CacheCreatedExpirySeconds,
+		config.KeyCacheModifiedExpirySeconds: cfg.CacheModifiedExpirySeconds,
+		config.KeyDomains:                    domains,
+		config.KeyHistoryMaxAgeSeconds:       cfg.HistoryMaxAgeSeconds,
+		config.KeyHistoryMaxEntries:          cfg.HistoryMaxEntries,
+		config.KeyRefreshInterval:            cfg.RefreshInterval,
+		config.KeyTemplates:                  templates,
+	}
+}
+
+func createEffectiveDomain(cfg *config.Config, client *client.Client, d *config.Domain, lookup bool, reveal bool) map[string]any {
+	domain := *d
+	templateName, _ := domain.GetTemplateName()
+	err := cfg.PrepareDomain(&domain)
+
+	if !reveal {
+		maskDomainSecrets(&domain)
+	}
+
+	values := map[string]any{}
+	_ = mapstructure.Decode(domain, &values)
+	values[config.KeyAddressPolicy] = domain.GetAddressPolicy()
+	values[config.KeyFamilies] = domain.GetFamilies()
+	values[config.KeyIp6PrefixLength] = domain.GetIp6PrefixLength()
+	values[keySource] = domain.Source
+
+	if templateName != "" {
+		values[keyTemplate] = templateName
+		values[keyTemplateSource] = cfg.TemplateSources[templateName]
+	}
+
+	if err != nil {
+		values[keyError] = err.Error()
+
+		return values
+	}
+
+	refreshDomains := []*config.Domain{&domain}
+	if domain.HasFamilyRefreshUrls() {
+		refreshDomains = domain.SplitByFamily()
+	}
+
+	for _, refreshDomain := range refreshDomains {
+		key := keyResolvedRefreshUrl
+		if domain.HasFamilyRefreshUrls() {
+			key = fmt.Sprintf("%s_%s", keyResolvedRefreshUrl, refreshDomain.Families[0])
+		}
+
+		if !lookup {
+			values[key] = client.BuildStaticRefreshUrl(refreshDomain)
+			continue
+		}
+
+		values[key], err = client.BuildRefreshUrl(context.Background(), refreshDomain)
+		if err != nil {
+			values[keyError] = err.Error()
+		}
+	}
+
+	return values
+}
+
+func maskDomainSecrets(domain *config.Domain) {
+	if domain.AuthPassword != "" {
+		domain.AuthPassword = maskedSecret
+	}
+
+	domain.RefreshUrl = maskUrlSecret(domain.RefreshUrl)
+	domain.RefreshUrlIp4 = maskUrlSecret(domain.RefreshUrlIp4)
+	domain.RefreshUrlIp6 = maskUrlSecret(domain.RefreshUrlIp6)
+}
+
+func maskUrlSecret(refreshUrl string) string {
+	parsedUrl, err := url.Parse(refreshUrl)
+	if err != nil || parsedUrl.User == nil {
+		return refreshUrl
+	}
+
+	password, ok := parsedUrl.User.Password()
+	if !ok || password == "" {
+		return refreshUrl
+	}
+
+	return strings.Replace(refreshUrl, ":"+password+"@", ":"+maskedSecret+"@", 1)
+}
+
+func printConfig(w io.Writer, values map[string]any, output string) error {
+	switch output {
+	case outputToml:
+		return toml.NewEncoder(w).Encode(values)
+	case outputJson:
+		encoder := json.NewEncoder(w)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(values)
+	case outputYaml:
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+
+		return encoder.Encode(values)
+	}
+
+	return fmt.Errorf("unsupported output format \"%s\"", output)
+}
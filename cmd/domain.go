@@ -1,28 +1,46 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
 	"log"
-	"net/http"
+	"os"
 	"strings"
 
 	"github.com/drieschel/yddns/internal/cache"
-	"github.com/drieschel/yddns/internal/client"
 	"github.com/drieschel/yddns/internal/config"
+	"github.com/drieschel/yddns/internal/netaddr"
+	"github.com/drieschel/yddns/pkg/yddns"
 	"github.com/spf13/cobra"
 )
 
 var (
-	flagAuthMethod    = createFlagName(config.KeyAuthMethod)
-	flagDomainName    = createFlagName(config.KeyDomainName)
-	flagHost          = createFlagName(config.KeyHost)
-	flagIp4Address    = createFlagName(config.KeyIp4Address)
-	flagIp6Address    = createFlagName(config.KeyIp6Address)
-	flagIp6HostId     = createFlagName(config.KeyIp6HostId)
-	flagPassword      = createFlagName(config.KeyPassword)
-	flagProtocol      = createFlagName(config.KeyProtocol)
-	flagRequestMethod = createFlagName(config.KeyRequestMethod)
-	flagUserAgent     = createFlagName(config.KeyUserAgent)
-	flagUsername      = createFlagName(config.KeyUsername)
+	flagAddressPolicy      = createFlagName(config.KeyAddressPolicy)
+	flagAuthMethod         = createFlagName(config.KeyAuthMethod)
+	flagDomainName         = createFlagName(config.KeyDomainName)
+	flagFamilies           = createFlagName(config.KeyFamilies)
+	flagHost               = createFlagName(config.KeyHost)
+	flagIp4Address         = createFlagName(config.KeyIp4Address)
+	flagIp6Address         = createFlagName(config.KeyIp6Address)
+	flagIp6HostId          = createFlagName(config.KeyIp6HostId)
+	flagIp6Interface       = createFlagName(config.KeyIp6Interface)
+	flagIp6InterfacePrefer = createFlagName(config.KeyIp6InterfacePrefer)
+	flagIp6InterfaceSuffix = createFlagName(config.KeyIp6InterfaceSuffix)
+	flagIp6Mac             = createFlagName(config.KeyIp6Mac)
+	flagIp6NeighborLookup  = createFlagName(config.KeyIp6NeighborLookup)
+	flagIp6Prefix          = createFlagName(config.KeyIp6Prefix)
+	flagIp6PrefixLength    = createFlagName(config.KeyIp6PrefixLength)
+	flagPassword           = createFlagName(config.KeyPassword)
+	flagProtocol           = createFlagName(config.KeyProtocol)
+	flagRefreshUrlIp4      = createFlagName(config.KeyRefreshUrlIp4)
+	flagRefreshUrlIp6      = createFlagName(config.KeyRefreshUrlIp6)
+	flagRequestMethod      = createFlagName(config.KeyRequestMethod)
+	flagResolver           = createFlagName(config.KeyResolver)
+	flagSkipIfResolved     = createFlagName(config.KeySkipIfResolved)
+	flagUserAgent          = createFlagName(config.KeyUserAgent)
+	flagUsername           = createFlagName(config.KeyUsername)
+	flagVerify             = createFlagName(config.KeyVerify)
+	flagVerifyTimeout      = createFlagName(config.KeyVerifyTimeout)
 
 	flagCacheCreatedLifetime  = createFlagName(config.KeyCacheCreatedExpirySeconds)
 	flagCacheModifiedLifetime = createFlagName(config.KeyCacheModifiedExpirySeconds)
@@ -39,17 +57,43 @@ var domainCmd = &cobra.Command{
 
 		err := cfg.PrepareDomain(domain)
 		if err != nil {
-			log.Fatal(err)
+			exitWithError(err)
 		}
 
-		client := client.NewClient(cfg.CreateFileCache(), &http.Client{})
+		output := getRefreshOutput(cmd)
+		updater := yddns.NewWithConfig(cfg, yddns.WithEventHandler(logWarningEvent))
+		if dryRun, _ := cmd.Flags().GetBool(flagDryRun); dryRun {
+			requests, err := buildDryRunRequests(context.Background(), updater, domain)
+			printErr := printDryRun(os.Stdout, requests, output)
+			if printErr != nil {
+				log.Fatal(printErr)
+			}
 
-		response, err := client.Refresh(domain)
-		if err != nil {
-			log.Fatal(err)
+			if err != nil {
+				exitWithError(err)
+			}
+
+			return
+		}
+
+		result, err := updater.Refresh(context.Background(), domain)
+		if output != outputText {
+			printErr := printRefreshResults(os.Stdout, []*yddns.Result{result}, output)
+			if printErr != nil {
+				log.Println(printErr)
+			}
+		} else if result.Action != yddns.ActionFailed {
+			log.Printf("provider responded \"%s\"", result)
 		}
 
-		log.Printf("provider responded \"%s\"", response)
+		if err != nil {
+			log.Print(err)
+			if result.HasFailed() {
+				os.Exit(exitCodeFailure)
+			}
+
+			os.Exit(exitCodeError)
+		}
 	},
 }
 
@@ -62,54 +106,102 @@ func init() {
 	domainCmd.Flags().String(flagIp4Address, "", "Set IPv4 address instead determining via wan request [<ip4>]")
 	domainCmd.Flags().String(flagIp6Address, "", "Set IPv6 address instead determining via wan request [<ip6>]")
 	domainCmd.Flags().String(flagIp6HostId, "", "Set IPv6 host id/interface id and use prefix + host id in the refresh url [<ip6>")
+	domainCmd.Flags().String(flagIp6Interface, "", "Determine IPv6 address/prefix from a local interface instead of via wan request [<interface>]")
+	domainCmd.Flags().String(flagIp6InterfacePrefer, "", "Prefer a stable address of the ip6 interface [eui64 | suffix]")
+	domainCmd.Flags().String(flagIp6InterfaceSuffix, "", "Set interface id suffix preferred by the suffix preference [::1234]")
+	domainCmd.Flags().String(flagIp6Mac, "", "Set mac address and use prefix + EUI-64 interface id in the refresh url [<ip6>]")
+	domainCmd.Flags().Bool(flagIp6NeighborLookup, false, "Look up the interface id of the ip6 mac in the IPv6 neighbor table instead of using EUI-64")
+	domainCmd.Flags().String(flagIp6Prefix, "", "Set static IPv6 prefix used with the host id instead of the wan IPv6 prefix [2001:db8::/56]")
+	domainCmd.Flags().Int(flagIp6PrefixLength, netaddr.Ip6PrefixLengthDefault, "Set IPv6 prefix length used to combine prefix and host id")
+	domainCmd.Flags().String(flagAddressPolicy, config.DefaultAddressPolicy, "Set policy for cgnat, private and loopback addresses [reject | warn | allow]")
+	domainCmd.Flags().StringSlice(flagFamilies, []string{}, "Set ip families to refresh [ip4,ip6] (default all)")
+	domainCmd.Flags().String(flagRefreshUrlIp4, "", "Set refresh url used for a separate IPv4 request")
+	domainCmd.Flags().String(flagRefreshUrlIp6, "", "Set refresh url used for a separate IPv6 request")
 	domainCmd.Flags().String(flagHost, "", "Set host name of the service in the refresh url [<host>]")
 	domainCmd.Flags().String(flagProtocol, config.DefaultProtocol, "Set protocol in the refresh url [<protocol>]")
 	domainCmd.Flags().String(flagAuthMethod, config.DefaultAuthMethod, "Set authentication method in refresh requests")
 	domainCmd.Flags().String(flagRequestMethod, config.DefaultRequestMethod, "Set request method in refresh requests")
 	domainCmd.Flags().String(flagUserAgent, "", "Set user agent in refresh requests")
+	domainCmd.Flags().Bool(flagVerify, false, "Verify via dns that the domain resolves to the refreshed ip addresses")
+	domainCmd.Flags().Bool(flagSkipIfResolved, false, "Skip refresh instead of using the cache if the domain already resolves to the current ip addresses")
+	domainCmd.Flags().String(flagResolver, "", "Set dns resolver used for verification and skip checks [host:port | authoritative]")
+	domainCmd.Flags().Int(flagVerifyTimeout, config.DefaultVerifyTimeout, "Set verification timeout in seconds")
+	domainCmd.Flags().Bool(flagDryRun, false, "Print the refresh request instead of sending it")
+	domainCmd.Flags().StringP(flagOutput, "o", outputText, fmt.Sprintf("Set output format [%s|%s|%s]", outputText, outputJson, outputTable))
 	domainCmd.Flags().Int(flagCacheModifiedLifetime, cache.ModifiedExpirySecondsDefault, "Set relative domain configuration cache lifetime in seconds [0 is disabled]")
 	domainCmd.Flags().Int(flagCacheCreatedLifetime, cache.CreatedExpirySecondsDefault, "Set max domain configuration cache lifetime in seconds [0 is disabled]")
 	domainCmd.Flags().SortFlags = false
 }
 
-func createConfig(cmd *cobra.Command) *config.Config {
-	cfg := config.NewConfig(version)
-	cfg.CacheCreatedExpirySeconds, _ = cmd.Flags().GetInt(flagCacheCreatedLifetime)
-	cfg.CacheModifiedExpirySeconds, _ = cmd.Flags().GetInt(flagCacheModifiedLifetime)
+func createConfig(cmd *cobra.Command) *yddns.Config {
+	cfg, err := yddns.NewConfig(version)
+	if err != nil {
+		exitWithError(err)
+	}
+
+	cacheCreatedLifetime, _ := cmd.Flags().GetInt(flagCacheCreatedLifetime)
+	cacheModifiedLifetime, _ := cmd.Flags().GetInt(flagCacheModifiedLifetime)
+	cfg.SetCacheExpirySeconds(cacheCreatedLifetime, cacheModifiedLifetime)
 
 	return cfg
 }
 
-func createDomain(cmd *cobra.Command, refreshUrl string) *config.Domain {
+func createDomain(cmd *cobra.Command, refreshUrl string) *yddns.Domain {
+	addressPolicy, _ := cmd.Flags().GetString(flagAddressPolicy)
 	authMethod, _ := cmd.Flags().GetString(flagAuthMethod)
 	domainName, _ := cmd.Flags().GetString(flagDomainName)
+	families, _ := cmd.Flags().GetStringSlice(flagFamilies)
 	host, _ := cmd.Flags().GetString(flagHost)
 	ip4Address, _ := cmd.Flags().GetString(flagIp4Address)
 	ip6Address, _ := cmd.Flags().GetString(flagIp6Address)
 	ip6HostId, _ := cmd.Flags().GetString(flagIp6HostId)
+	ip6Interface, _ := cmd.Flags().GetString(flagIp6Interface)
+	ip6InterfacePrefer, _ := cmd.Flags().GetString(flagIp6InterfacePrefer)
+	ip6InterfaceSuffix, _ := cmd.Flags().GetString(flagIp6InterfaceSuffix)
+	ip6Mac, _ := cmd.Flags().GetString(flagIp6Mac)
+	ip6NeighborLookup, _ := cmd.Flags().GetBool(flagIp6NeighborLookup)
+	ip6Prefix, _ := cmd.Flags().GetString(flagIp6Prefix)
+	ip6PrefixLength, _ := cmd.Flags().GetInt(flagIp6PrefixLength)
 	password, _ := cmd.Flags().GetString(flagPassword)
 	protocol, _ := cmd.Flags().GetString(flagProtocol)
+	refreshUrlIp4, _ := cmd.Flags().GetString(flagRefreshUrlIp4)
+	refreshUrlIp6, _ := cmd.Flags().GetString(flagRefreshUrlIp6)
 	requestMethod, _ := cmd.Flags().GetString(flagRequestMethod)
+	dnsResolver, _ := cmd.Flags().GetString(flagResolver)
+	skipIfResolved, _ := cmd.Flags().GetBool(flagSkipIfResolved)
 	userAgent, _ := cmd.Flags().GetString(flagUserAgent)
 	username, _ := cmd.Flags().GetString(flagUsername)
-
-	template := &config.Template{
-		AuthMethod:    authMethod,
-		Host:          host,
-		Protocol:      protocol,
-		RefreshUrl:    refreshUrl,
-		RequestMethod: requestMethod,
-		UserAgent:     userAgent,
-	}
-
-	return &config.Domain{
-		AuthUser:     username,
-		AuthPassword: password,
-		DomainName:   domainName,
-		Ip4Address:   ip4Address,
-		Ip6Address:   ip6Address,
-		Ip6HostId:    ip6HostId,
-		Template:     *template,
+	verify, _ := cmd.Flags().GetBool(flagVerify)
+	verifyTimeout, _ := cmd.Flags().GetInt(flagVerifyTimeout)
+
+	return &yddns.Domain{
+		AddressPolicy:      addressPolicy,
+		AuthMethod:         authMethod,
+		DomainName:         domainName,
+		Families:           families,
+		Host:               host,
+		Ip4Address:         ip4Address,
+		Ip6Address:         ip6Address,
+		Ip6HostId:          ip6HostId,
+		Ip6Interface:       ip6Interface,
+		Ip6InterfacePrefer: ip6InterfacePrefer,
+		Ip6InterfaceSuffix: ip6InterfaceSuffix,
+		Ip6Mac:             ip6Mac,
+		Ip6NeighborLookup:  ip6NeighborLookup,
+		Ip6Prefix:          ip6Prefix,
+		Ip6PrefixLength:    ip6PrefixLength,
+		Password:           password,
+		Protocol:           protocol,
+		RefreshUrl:         refreshUrl,
+		RefreshUrlIp4:      refreshUrlIp4,
+		RefreshUrlIp6:      refreshUrlIp6,
+		RequestMethod:      requestMethod,
+		Resolver:           dnsResolver,
+		SkipIfResolved:     skipIfResolved,
+		UserAgent:          userAgent,
+		Username:           username,
+		Verify:             verify,
+		VerifyTimeout:      verifyTimeout,
 	}
 }
 
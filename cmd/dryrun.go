@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/drieschel/yddns/pkg/yddns"
+)
+
+const flagDryRun = "dry-run"
+
+type dryRunRequest struct {
+	Domain  string            `json:"domain"`
+	Family  string            `json:"family,omitempty"`
+	Method  string            `json:"method"`
+	Url     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body,omitempty"`
+}
+
+func buildDryRunRequests(ctx context.Context, updater *yddns.Updater, domain *yddns.Domain) ([]*dryRunRequest, error) {
+	redactedDomain := *domain
+	if redactedDomain.Username != "" {
+		redactedDomain.Username = maskedSecret
+	}
+
+	if redactedDomain.Password != "" {
+		redactedDomain.Password = maskedSecret
+	}
+
+	requests, err := updater.BuildRequests(ctx, &redactedDomain)
+
+	var dryRunRequests []*dryRunRequest
+	for _, request := range requests {
+		headers := map[string]string{}
+		for name := range request.Header {
+			headers[name] = redactHeader(name, request.Header.Get(name))
+		}
+
+		body := ""
+		if request.Body != nil {
+			data, _ := io.ReadAll(request.Body)
+			body = string(data)
+		}
+
+		dryRunRequests = append(dryRunRequests, &dryRunRequest{
+			Domain:  domain.DomainName,
+			Family:  request.Family,
+			Method:  request.Method,
+			Url:     maskUrlSecret(request.URL.String()),
+			Headers: headers,
+			Body:    body,
+		})
+	}
+
+	return dryRunRequests, err
+}
+
+func printDryRun(w io.Writer, requests []*dryRunRequest, output string) error {
+	switch output {
+	case outputText:
+		for _, request := range requests {
+			label := strings.TrimSpace(request.Domain)
+			if request.Family != "" {
+				label = strings.TrimSpace(fmt.Sprintf("%s (%s)", request.Domain, request.Family))
+			}
+
+			if label == "" {
+				label = "request"
+			}
+
+			fmt.Fprintf(w, "%s: %s %s\n", label, request.Method, request.Url)
+			for _, name := range slices.Sorted(maps.Keys(request.Headers)) {
+				fmt.Fprintf(w, "  %s: %s\n", name, request.Headers[name])
+			}
+
+			body := request.Body
+			if body == "" {
+				body = "(empty)"
+			}
+
+			fmt.Fprintf(w, "  Body: %s\n", body)
+		}
+
+		return nil
+
+	case outputJson:
+		if requests == nil {
+			requests = []*dryRunRequest{}
+		}
+
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+
+		return encoder.Encode(requests)
+
+	case outputTable:
+		writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "DOMAIN\tFAMILY\tMETHOD\tURL\tBODY")
+		for _, request := range requests {
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n", request.Domain, request.Family, request.Method, request.Url, strings.Join(strings.Fields(request.Body), " "))
+		}
+
+		return writer.Flush()
+	}
+
+	return fmt.Errorf("unsupported output format \"%s\"", output)
+}
+
+func redactHeader(name string, value string) string {
+	if name != "Authorization" {
+		return value
+	}
+
+	scheme, _, found := strings.Cut(value, " ")
+	if !found {
+		return maskedSecret
+	}
+
+	return fmt.Sprintf("%s %s", scheme, maskedSecret)
+}
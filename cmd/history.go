@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/drieschel/yddns/internal/config"
+	"github.com/drieschel/yddns/internal/history"
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagFamily = "family"
+	flagOutput = "output"
+	flagSince  = "since"
+	flagUntil  = "until"
+
+	outputCsv   = "csv"
+	outputJson  = "json"
+	outputTable = "table"
+	outputText  = "text"
+	outputToml  = "toml"
+	outputYaml  = "yaml"
+)
+
+var (
+	historyTimeLayouts = []string{time.RFC3339, time.DateTime, "2006-01-02 15:04", time.DateOnly}
+	historyColumns     = []string{"time", "domain", "family", "old_address", "new_address", "result", "message"}
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show the history of ip changes and refresh attempts",
+	Long:  `Show the history of ip changes and refresh attempts filtered by domain, family and time range`,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		domainName, _ := cmd.Flags().GetString(flagDomainName)
+		family, _ := cmd.Flags().GetString(flagFamily)
+		output, _ := cmd.Flags().GetString(flagOutput)
+
+		since, err := parseHistoryTime(cmd, flagSince)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		until, err := parseHistoryTime(cmd, flagUntil)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		cfg, err := config.NewConfig(version)
+		if err != nil {
+			exitWithError(err)
+		}
+
+		fileHistory := cfg.CreateFileHistory()
+		fileHistory.SetWarningHandler(logWarning)
+
+		entries, err := fileHistory.Find(history.Filter{Domain: domainName, Family: family, Since: since, Until: until})
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		err = printHistory(os.Stdout, entries, output)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+
+	historyCmd.Flags().String(flagDomainName, "", "Show entries of this domain only")
+	historyCmd.Flags().String(flagFamily, "", fmt.Sprintf("Show entries of this ip family only [%s|%s]", config.KeyIp4, config.KeyIp6))
+	historyCmd.Flags().String(flagSince, "", "Show entries since this local time [2006-01-02 15:04:05]")
+	historyCmd.Flags().String(flagUntil, "", "Show entries until this local time [2006-01-02 15:04:05]")
+	historyCmd.Flags().StringP(flagOutput, "o", outputTable, fmt.Sprintf("Set output format [%s|%s|%s]", outputTable, outputJson, outputCsv))
+	historyCmd.Flags().SortFlags = false
+}
+
+func parseHistoryTime(cmd *cobra.Command, flag string) (time.Time, error) {
+	value, _ := cmd.Flags().GetString(flag)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	for _, layout := range historyTimeLayouts {
+		parsed, err := time.ParseInLocation(layout, value, time.Local)
+		if err == nil {
+			return parsed, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid time \"%s\" for --%s", value, flag)
+}
+
+func printHistory(w io.Writer, entries []*history.Entry, output string) error {
+	switch output {
+	case outputJson:
+		if entries == nil {
+			entries = []*history.Entry{}
+		}
+
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(entries)
+
+	case outputCsv:
+		writer := csv.NewWriter(w)
+		err := writer.Write(historyColumns)
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			err = writer.Write(historyRow(entry))
+			if err != nil {
+				return err
+			}
+		}
+
+		writer.Flush()
+
+		return writer.Error()
+
+	case outputTable:
+		writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "TIME\tDOMAIN\tFAMILY\tOLD ADDRESS\tNEW ADDRESS\tRESULT\tMESSAGE")
+		for _, entry := range entries {
+			row := historyRow(entry)
+			row[0] = entry.Time.Local().Format(time.DateTime)
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", row[0], row[1], row[2], row[3], row[4], row[5], row[6])
+		}
+
+		return writer.Flush()
+	}
+
+	return fmt.Errorf("unsupported output format \"%s\"", output)
+}
+
+func historyRow(entry *history.Entry) []string {
+	return []string{entry.Time.Format(time.RFC3339), entry.Domain, entry.Family, entry.OldAddress, entry.NewAddress, entry.Result, entry.Message}
+}
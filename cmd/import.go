@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"reflect"
+
+	"github.com/drieschel/yddns/internal/config"
+	"github.com/drieschel/yddns/internal/importer"
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/spf13/cobra"
+)
+
+const importStdin = "-"
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Convert a config of another dyn dns client",
+	Long:  `Convert a config of another dyn dns client into a yddns config, unmappable settings are reported on stderr`,
+}
+
+var importDdclientCmd = &cobra.Command{
+	Use:   "ddclient <file>",
+	Short: "Convert a ddclient config",
+	Long:  `Convert a ddclient config (ddclient.conf) into a yddns config, use "-" to read from stdin`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runImport(cmd, args[0], importer.ParseDdclient)
+	},
+}
+
+var importInadynCmd = &cobra.Command{
+	Use:   "inadyn <file>",
+	Short: "Convert an inadyn config",
+	Long:  `Convert an inadyn config (inadyn.conf) into a yddns config, use "-" to read from stdin`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runImport(cmd, args[0], importer.ParseInadyn)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+	importCmd.AddCommand(importDdclientCmd, importInadynCmd)
+	for _, cmd := range []*cobra.Command{importDdclientCmd, importInadynCmd} {
+		cmd.Flags().StringP(flagOutput, "o", outputToml, fmt.Sprintf("Set output format [%s|%s|%s]", outputToml, outputJson, outputYaml))
+	}
+}
+
+func runImport(cmd *cobra.Command, file string, parse func(r io.Reader) (*importer.Result, error)) {
+	output, _ := cmd.Flags().GetString(flagOutput)
+
+	reader := io.Reader(os.Stdin)
+	if file != importStdin {
+		f, err := os.Open(file)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		defer f.Close()
+		reader = f
+	}
+
+	result, err := parse(reader)
+	if err != nil {
+		log.Fatalf("%s: %s", file, err)
+	}
+
+	for _, problem := range result.Problems {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", file, problem)
+	}
+
+	cfg, err := config.NewConfig(version)
+	if err != nil {
+		exitWithError(err)
+	}
+
+	for _, domain := range result.Domains {
+		for _, err = range cfg.ValidateDomain(domain) {
+			fmt.Fprintf(os.Stderr, "%s: \"%s\": %s\n", file, domain.DomainName, err)
+		}
+	}
+
+	err = printConfig(os.Stdout, createImportConfig(result), output)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func createImportConfig(result *importer.Result) map[string]any {
+	domains := []map[string]any{}
+	for _, domain := range result.Domains {
+		values := map[string]any{}
+		_ = mapstructure.Decode(domain, &values)
+
+		for key, value := range values {
+			if reflect.ValueOf(value).IsZero() {
+				delete(values, key)
+			}
+		}
+
+		domains = append(domains, values)
+	}
+
+	values := map[string]any{config.KeyDomains: domains}
+	if result.RefreshInterval > 0 {
+		values[config.KeyRefreshInterval] = result.RefreshInterval
+	}
+
+	return values
+}
@@ -0,0 +1,323 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/drieschel/yddns/internal/config"
+	"github.com/drieschel/yddns/pkg/yddns"
+	"github.com/pelletier/go-toml/v2"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+const (
+	flagForce          = "force"
+	flagNonInteractive = "non-interactive"
+	flagProvider       = "provider"
+
+	initFileName = "config.toml"
+)
+
+var initConfigTemplate = template.Must(template.New(initFileName).Funcs(template.FuncMap{"value": createTomlValue}).Parse(`# generated by "yddns init", check config.toml.example for all options
+
+# refresh interval in seconds for periodical updates
+refresh_interval={{ .RefreshInterval }}
+
+# every domain you want to update needs an own [[domains]] block.
+# define as many [[domains]] blocks as needed.
+[[domains]]
+
+# your domain with dynamic ip
+domain={{ value .Domain.DomainName }}
+
+# refresh url template of your provider ("yddns template show {{ .Provider }}" shows its values)
+refresh_url={{ value .Domain.RefreshUrl }}
+
+# authentication on dyn dns service
+{{ if .Domain.AuthUser }}username={{ value .Domain.AuthUser }}{{ else }}# username=""{{ end }}
+{{ if .Domain.AuthPassword }}password={{ value .Domain.AuthPassword }}{{ else }}# password=""{{ end }}
+
+# ip families to refresh [ip4, ip6]
+{{ if .Domain.Families }}families={{ value .Domain.Families }}{{ else }}# families=["ip4", "ip6"]{{ end }}
+`))
+
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Create a config file",
+	Long:  `Create a commented config file for a domain of a provider template, asking for all values not given by flags`,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		dir, _ := cmd.Flags().GetString(flagDir)
+		domainName, _ := cmd.Flags().GetString(flagDomainName)
+		dryRun, _ := cmd.Flags().GetBool(flagDryRun)
+		families, _ := cmd.Flags().GetStringSlice(flagFamilies)
+		force, _ := cmd.Flags().GetBool(flagForce)
+		nonInteractive, _ := cmd.Flags().GetBool(flagNonInteractive)
+		password, _ := cmd.Flags().GetString(flagPassword)
+		provider, _ := cmd.Flags().GetString(flagProvider)
+		username, _ := cmd.Flags().GetString(flagUsername)
+
+		cfg, err := config.NewConfig(version)
+		if err != nil {
+			exitWithError(err)
+		}
+
+		interactive := !nonInteractive && term.IsTerminal(int(os.Stdin.Fd()))
+		p := &prompter{reader: bufio.NewReader(os.Stdin), writer: os.Stdout}
+
+		if interactive {
+			provider, err = p.askProvider(cfg, provider)
+			if err == nil && domainName == "" {
+				domainName, err = p.ask("Domain", "")
+			}
+
+			if err == nil && username == "" {
+				username, err = p.ask("Username (leave empty if not required)", "")
+			}
+
+			if err == nil && password == "" {
+				password, err = p.askSecret("Password or token")
+			}
+
+			if err == nil && !cmd.Flags().Changed(flagFamilies) {
+				families, err = p.askList("IP families", families)
+			}
+
+			if err == nil && !cmd.Flags().Changed(flagDryRun) {
+				dryRun, err = p.confirm("Print the refresh request(s) as dry-run", false)
+			}
+
+			if err == nil && !cmd.Flags().Changed(flagDir) {
+				dir, err = p.ask("Directory of the config file", dir)
+			}
+
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
+
+		if provider == "" || domainName == "" {
+			log.Fatalf("a provider and a domain are required (--%s and --%s)", flagProvider, flagDomainName)
+		}
+
+		if _, err = cfg.GetTemplate(provider); err != nil {
+			exitWithError(err)
+		}
+
+		domain := &config.Domain{
+			Template:     config.Template{RefreshUrl: config.RefreshUrlTemplatePrefix + provider},
+			AuthUser:     username,
+			AuthPassword: password,
+			DomainName:   domainName,
+			Families:     families,
+		}
+
+		errs := cfg.ValidateDomain(domain)
+		for _, err = range errs {
+			log.Printf("domain \"%s\": %s", domain.DomainName, err)
+		}
+
+		if len(errs) > 0 {
+			os.Exit(exitCodeValidation)
+		}
+
+		if dryRun {
+			err = printInitDryRun(domain)
+			if err != nil {
+				log.Printf("An error occurred when building the refresh request of %s: %s\n", domain.DomainName, err)
+			}
+		}
+
+		filePath := filepath.Join(dir, initFileName)
+		err = writeInitConfig(filePath, provider, domain, force)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		fmt.Printf("config file %s created\n", filePath)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(initCmd)
+	initCmd.Flags().String(flagProvider, "", "Set provider template (yddns template list)")
+	initCmd.Flags().String(flagDomainName, "", "Set domain name")
+	initCmd.Flags().String(flagUsername, "", "Set username")
+	initCmd.Flags().String(flagPassword, "", "Set password or token")
+	initCmd.Flags().StringSlice(flagFamilies, config.SupportedFamilies, "Set ip families to refresh")
+	initCmd.Flags().Bool(flagDryRun, false, "Print the refresh request(s) of the domain before writing the config file")
+	initCmd.Flags().String(flagDir, config.Dirs[0], "Set directory of the config file")
+	initCmd.Flags().Bool(flagForce, false, "Overwrite an existing config file")
+	initCmd.Flags().Bool(flagNonInteractive, false, "Do not ask for values not given by flags")
+	initCmd.Flags().SortFlags = false
+}
+
+func printInitDryRun(domain *config.Domain) error {
+	cfg, err := yddns.NewConfig(version)
+	if err != nil {
+		return err
+	}
+
+	publicDomain := &yddns.Domain{DomainName: domain.DomainName, Families: domain.Families, Password: domain.AuthPassword, RefreshUrl: domain.RefreshUrl, Username: domain.AuthUser}
+	requests, err := buildDryRunRequests(context.Background(), yddns.NewWithConfig(cfg, yddns.WithEventHandler(logWarningEvent)), publicDomain)
+	printErr := printDryRun(os.Stdout, requests, outputText)
+	if printErr != nil {
+		return printErr
+	}
+
+	return err
+}
+
+func writeInitConfig(filePath string, provider string, domain *config.Domain, force bool) error {
+	var buffer bytes.Buffer
+	err := initConfigTemplate.Execute(&buffer, map[string]any{"Domain": domain, "Provider": provider, "RefreshInterval": config.DefaultRefreshInterval})
+	if err != nil {
+		return err
+	}
+
+	err = writeFile(filePath, buffer.Bytes(), 0600, force)
+	if os.IsExist(err) {
+		return fmt.Errorf("config file %s already exists (use --%s to overwrite it)", filePath, flagForce)
+	}
+
+	return err
+}
+
+func writeFile(filePath string, data []byte, perm os.FileMode, force bool) error {
+	err := os.MkdirAll(filepath.Dir(filePath), 0755)
+	if err != nil {
+		return err
+	}
+
+	flags := os.O_CREATE | os.O_WRONLY | os.O_EXCL
+	if force {
+		flags = os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	}
+
+	file, err := os.OpenFile(filePath, flags, perm)
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	err = file.Chmod(perm)
+	if err != nil {
+		return err
+	}
+
+	_, err = file.Write(data)
+
+	return err
+}
+
+func createTomlValue(value any) (string, error) {
+	data, err := toml.Marshal(value)
+
+	return string(data), err
+}
+
+type prompter struct {
+	reader *bufio.Reader
+	writer io.Writer
+}
+
+func (p *prompter) ask(label string, defaultValue string) (string, error) {
+	if defaultValue != "" {
+		fmt.Fprintf(p.writer, "%s [%s]: ", label, defaultValue)
+	} else {
+		fmt.Fprintf(p.writer, "%s: ", label)
+	}
+
+	answer, err := p.reader.ReadString('\n')
+	if err != nil && (err != io.EOF || answer == "") {
+		return "", err
+	}
+
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		return defaultValue, nil
+	}
+
+	return answer, nil
+}
+
+func (p *prompter) askSecret(label string) (string, error) {
+	fmt.Fprintf(p.writer, "%s: ", label)
+	secret, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(p.writer)
+
+	return strings.TrimSpace(string(secret)), err
+}
+
+func (p *prompter) askList(label string, defaultValues []string) ([]string, error) {
+	answer, err := p.ask(label, strings.Join(defaultValues, ","))
+	if err != nil {
+		return nil, err
+	}
+
+	var values []string
+	for _, value := range strings.Split(answer, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+
+	return values, nil
+}
+
+func (p *prompter) askProvider(cfg *config.Config, provider string) (string, error) {
+	if provider != "" {
+		return provider, nil
+	}
+
+	providers := slices.Sorted(maps.Keys(cfg.Templates))
+
+	for i, name := range providers {
+		template, _ := cfg.GetTemplate(name)
+		fmt.Fprintf(p.writer, "%3d) %-20s %s\n", i+1, name, template.Host)
+	}
+
+	for {
+		answer, err := p.ask("Provider (number or name)", "")
+		if err != nil {
+			return "", err
+		}
+
+		if number, err := strconv.Atoi(answer); err == nil && number > 0 && number <= len(providers) {
+			return providers[number-1], nil
+		}
+
+		if slices.Contains(providers, answer) {
+			return answer, nil
+		}
+
+		fmt.Fprintf(p.writer, "unknown provider \"%s\"\n", answer)
+	}
+}
+
+func (p *prompter) confirm(label string, defaultValue bool) (bool, error) {
+	defaultAnswer := "y/N"
+	if defaultValue {
+		defaultAnswer = "Y/n"
+	}
+
+	answer, err := p.ask(fmt.Sprintf("%s (%s)", label, defaultAnswer), "")
+	if err != nil || answer == "" {
+		return defaultValue, err
+	}
+
+	return strings.HasPrefix(strings.ToLower(answer), "y"), nil
+}
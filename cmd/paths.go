@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/drieschel/yddns/internal/config"
+	"github.com/spf13/cobra"
+)
+
+const (
+	pathKindCache     = "cache"
+	pathKindConfD     = "conf_d"
+	pathKindConfDir   = "config_dir"
+	pathKindConfFile  = "config_file"
+	pathKindHistory   = "history"
+	pathKindTemplates = "templates"
+
+	pathStatusExists  = "exists"
+	pathStatusMissing = "missing"
+	pathStatusUsed    = "used"
+)
+
+type pathEntry struct {
+	Kind   string `json:"kind"`
+	Path   string `json:"path"`
+	Status string `json:"status"`
+}
+
+var pathsCmd = &cobra.Command{
+	Use:   "paths",
+	Short: "Print the directories and files used",
+	Long:  `Print the config search path in order of precedence, the loaded config files, the template directories and the cache and history directories`,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString(flagOutput)
+		cfg, err := config.NewOptionalFileConfig(version)
+		if err != nil {
+			exitWithError(err)
+		}
+
+		err = printPathEntries(os.Stdout, createPathEntries(cfg), output)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pathsCmd)
+	pathsCmd.Flags().StringP(flagConfigFile, "c", "", "Override default config using absolute file path")
+	pathsCmd.Flags().StringP(flagOutput, "o", outputTable, fmt.Sprintf("Set output format [%s|%s]", outputTable, outputJson))
+}
+
+func createPathEntries(cfg *config.Config) []*pathEntry {
+	var entries []*pathEntry
+	if config.FilePath == "" {
+		for _, dir := range config.Dirs {
+			entries = append(entries, createPathEntry(pathKindConfDir, dir))
+		}
+	}
+
+	if cfg.File != "" {
+		entries = append(entries, &pathEntry{Kind: pathKindConfFile, Path: cfg.File, Status: pathStatusUsed})
+	}
+
+	for _, dir := range config.GetConfDirs() {
+		entries = append(entries, createPathEntry(pathKindConfD, dir))
+	}
+
+	for _, file := range cfg.ConfFiles {
+		entries = append(entries, &pathEntry{Kind: pathKindConfFile, Path: file, Status: pathStatusUsed})
+	}
+
+	for _, dir := range config.Dirs {
+		entries = append(entries, createPathEntry(pathKindTemplates, filepath.Join(dir, config.DirNameTemplates)))
+	}
+
+	entries = append(entries, &pathEntry{Kind: pathKindTemplates, Path: config.EmbeddedTemplatesSource, Status: pathStatusUsed})
+	entries = append(entries, createPathEntry(pathKindCache, cfg.CacheDir))
+	entries = append(entries, createPathEntry(pathKindHistory, filepath.Join(cfg.StateDir, config.DirNameHistory)))
+
+	return entries
+}
+
+func createPathEntry(kind string, path string) *pathEntry {
+	status := pathStatusMissing
+	if _, err := os.Stat(path); err == nil {
+		status = pathStatusExists
+	}
+
+	return &pathEntry{Kind: kind, Path: path, Status: status}
+}
+
+func printPathEntries(w io.Writer, entries []*pathEntry, output string) error {
+	switch output {
+	case outputJson:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(entries)
+
+	case outputTable:
+		writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "KIND\tPATH\tSTATUS")
+		for _, entry := range entries {
+			fmt.Fprintf(writer, "%s\t%s\t%s\n", entry.Kind, entry.Path, entry.Status)
+		}
+
+		return writer.Flush()
+	}
+
+	return fmt.Errorf("unsupported output format \"%s\"", output)
+}
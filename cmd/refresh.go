@@ -1,17 +1,31 @@
 package cmd
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"log"
-	"net/http"
+	"maps"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"text/tabwriter"
 	"time"
 
-	"github.com/drieschel/yddns/internal/client"
 	"github.com/drieschel/yddns/internal/config"
+	"github.com/drieschel/yddns/internal/systemd"
+	"github.com/drieschel/yddns/pkg/yddns"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 const (
 	flagConfigFile      = "config-file"
+	flagFailFast        = "fail-fast"
 	flagRefreshInterval = "refresh-interval"
 	flagPeriodically    = "periodically"
 )
@@ -31,40 +45,92 @@ var refreshCmd = &cobra.Command{
 			log.Fatal(err)
 		}
 
-		cfg := config.NewFileConfig(version)
-
-		domains, err := cfg.PrepareAndGetDomains()
+		dryRun, err := cmd.Flags().GetBool(flagDryRun)
 		if err != nil {
 			log.Fatal(err)
 		}
 
-		if refreshInterval == 0 {
-			refreshInterval = cfg.RefreshInterval
+		failFast, err := cmd.Flags().GetBool(flagFailFast)
+		if err != nil {
+			log.Fatal(err)
 		}
 
-		cacheLifetime := cfg.CacheModifiedExpirySeconds
-		if periodically && cacheLifetime > 0 && cacheLifetime < (refreshInterval+2*len(domains)) {
-			cfg.CacheModifiedExpirySeconds = refreshInterval + 2*len(domains)
+		output := getRefreshOutput(cmd)
+
+		cfg, err := yddns.LoadConfig(version)
+		if err != nil {
+			exitWithError(err)
 		}
 
-		client := client.NewClient(cfg.CreateFileCache(), &http.Client{})
+		domains, err := cfg.Domains()
+		if err != nil {
+			exitWithError(err)
+		}
 
-		for {
-			client.Clear()
+		if dryRun {
+			updater := yddns.NewWithConfig(cfg, yddns.WithEventHandler(logWarningEvent))
+			var dryRunRequests []*dryRunRequest
+			var errs []error
 			for _, domain := range domains {
-				response, err := client.Refresh(domain)
+				requests, err := buildDryRunRequests(context.Background(), updater, domain)
+				dryRunRequests = append(dryRunRequests, requests...)
 				if err != nil {
-					log.Printf("An error occurred when refreshing %s: %s\n", domain.DomainName, err)
-				} else {
-					log.Printf("%s: %s", domain.DomainName, response)
+					errs = append(errs, fmt.Errorf("building the refresh request of %s (%s) failed: %w", domain.DomainName, domain.Source, err))
 				}
 			}
 
-			if !periodically {
-				break
+			err = printDryRun(os.Stdout, dryRunRequests, output)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			if len(errs) > 0 {
+				exitWithError(errors.Join(errs...))
 			}
 
-			time.Sleep(time.Duration(refreshInterval) * time.Second)
+			return
+		}
+
+		if refreshInterval == 0 {
+			refreshInterval = cfg.RefreshInterval()
+		}
+
+		cacheCreatedLifetime, cacheModifiedLifetime := cfg.CacheExpirySeconds()
+		if periodically && cacheModifiedLifetime > 0 && cacheModifiedLifetime < (refreshInterval+2*len(domains)) {
+			cfg.SetCacheExpirySeconds(cacheCreatedLifetime, refreshInterval+2*len(domains))
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		notifier := systemd.NewSocketNotifierWithDefaultValues()
+		watchdogInterval, err := systemd.WatchdogInterval()
+		if err != nil {
+			log.Println(err)
+		}
+
+		watchdog := systemd.NewWatchdog(notifier, watchdogInterval)
+		updater := yddns.NewWithConfig(cfg, yddns.WithEventHandler(createRefreshEventHandler(output)), yddns.WithEventHandler(createSystemdEventHandler(notifier, watchdog)), yddns.WithFailFast(failFast))
+		if !periodically {
+			results, err := updater.RefreshAll(ctx)
+			exitCode := getRefreshExitCode(results, len(domains), err)
+			if exitCode != 0 {
+				stop()
+				os.Exit(exitCode)
+			}
+
+			return
+		}
+
+		go watchdog.Run(ctx, func(err error) {
+			log.Printf("An error occurred when notifying systemd: %s\n", err)
+		})
+
+		notifySystemd(notifier, systemd.StateReady, systemd.Status(fmt.Sprintf("Refreshing %d domains every %d seconds", len(domains), refreshInterval)))
+		err = updater.Run(ctx, time.Duration(refreshInterval)*time.Second)
+		notifySystemd(notifier, systemd.StateStopping)
+		if err != nil && !errors.Is(err, context.Canceled) {
+			exitWithError(err)
 		}
 	},
 }
@@ -74,15 +140,133 @@ func init() {
 	refreshCmd.Flags().StringP(flagConfigFile, "c", "", "Override default config using absolute file path")
 	refreshCmd.Flags().BoolP(flagPeriodically, "p", false, "Refresh periodically")
 	refreshCmd.Flags().IntP(flagRefreshInterval, "i", 0, "Define refresh interval in seconds")
+	refreshCmd.Flags().Bool(flagDryRun, false, "Print the refresh requests instead of sending them")
+	refreshCmd.Flags().Bool(flagFailFast, false, "Stop refreshing at the first failing domain")
+	refreshCmd.Flags().StringP(flagOutput, "o", outputText, fmt.Sprintf("Set output format [%s|%s|%s]", outputText, outputJson, outputTable))
 
 	cobra.OnInitialize(initConfig)
 }
 
-func initConfig() {
-	configFile, err := refreshCmd.Flags().GetString(flagConfigFile)
-	if err != nil {
-		log.Fatal(err)
+func getRefreshOutput(cmd *cobra.Command) string {
+	output, _ := cmd.Flags().GetString(flagOutput)
+	if !slices.Contains([]string{outputText, outputJson, outputTable}, output) {
+		log.Fatalf("unsupported output format \"%s\"", output)
+	}
+
+	return output
+}
+
+func getRefreshExitCode(results []*yddns.Result, domainCount int, err error) int {
+	failed := max(domainCount-len(results), 0)
+	for _, result := range results {
+		if result.HasFailed() {
+			failed++
+		}
+	}
+
+	switch {
+	case failed == 0 && err == nil:
+		return 0
+	case failed == domainCount:
+		return exitCodeFailure
+	}
+
+	return exitCodePartialFailure
+}
+
+func createRefreshEventHandler(output string) yddns.EventHandler {
+	if output == outputText {
+		return logRefreshEvent
+	}
+
+	return func(event *yddns.Event) {
+		switch event.Type {
+		case yddns.EventRefreshAllFinished:
+			err := printRefreshResults(os.Stdout, event.Results, output)
+			if err != nil {
+				log.Println(err)
+			}
+
+		case yddns.EventPruneFailed, yddns.EventWarning:
+			logRefreshEvent(event)
+		}
+	}
+}
+
+func logRefreshEvent(event *yddns.Event) {
+	switch event.Type {
+	case yddns.EventRefreshUpdated, yddns.EventRefreshSkipped:
+		log.Printf("%s (%s): %s", event.Domain.DomainName, event.Domain.Source, event.Result)
+
+	case yddns.EventRefreshFailed:
+		if event.Result.Action != yddns.ActionFailed {
+			log.Printf("%s (%s): %s", event.Domain.DomainName, event.Domain.Source, event.Result)
+		}
+
+		log.Printf("An error occurred when refreshing %s (%s): %s\n", event.Domain.DomainName, event.Domain.Source, event.Err)
+
+	case yddns.EventPruneFailed:
+		log.Printf("An error occurred when pruning the history: %s\n", event.Err)
+
+	case yddns.EventWarning:
+		logWarning(event.Err)
+	}
+}
+
+func logWarningEvent(event *yddns.Event) {
+	if event.Type == yddns.EventWarning {
+		logWarning(event.Err)
 	}
+}
+
+func printRefreshResults(w io.Writer, results []*yddns.Result, output string) error {
+	switch output {
+	case outputJson:
+		if results == nil {
+			results = []*yddns.Result{}
+		}
+
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(results)
 
-	config.FilePath = configFile
+	case outputTable:
+		writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "DOMAIN\tACTION\tADDRESSES\tHTTP STATUS\tDURATION\tMESSAGE")
+		for _, result := range results {
+			httpStatus := ""
+			if result.HttpStatus > 0 {
+				httpStatus = fmt.Sprint(result.HttpStatus)
+			}
+
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%s\n", result.Domain, result.Action, formatAddresses(result.Addresses), httpStatus, result.Duration.Round(time.Millisecond), strings.Join(strings.Fields(result.String()), " "))
+		}
+
+		return writer.Flush()
+	}
+
+	return fmt.Errorf("unsupported output format \"%s\"", output)
+}
+
+func formatAddresses(addresses map[string]string) string {
+	var formatted []string
+	for _, family := range slices.Sorted(maps.Keys(addresses)) {
+		formatted = append(formatted, addresses[family])
+	}
+
+	return strings.Join(formatted, ", ")
+}
+
+func initConfig() {
+	for _, flags := range []*pflag.FlagSet{refreshCmd.Flags(), configCmd.PersistentFlags(), templateCmd.PersistentFlags(), pathsCmd.Flags(), systemdGenerateCmd.Flags()} {
+		configFile, err := flags.GetString(flagConfigFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if configFile != "" {
+			config.FilePath = configFile
+		}
+	}
 }
@@ -1,12 +1,24 @@
 package cmd
 
 import (
+	"errors"
+	"log"
 	"os"
 
 	"github.com/drieschel/yddns/internal/config"
 	"github.com/spf13/cobra"
 )
 
+const (
+	exitCodeError            = 1
+	exitCodePartialFailure   = 2
+	exitCodeConfigNotFound   = 3
+	exitCodeConfigParse      = 4
+	exitCodeTemplateNotFound = 5
+	exitCodeValidation       = 6
+	exitCodeFailure          = 7
+)
+
 var (
 	version = config.DefaultAppVersion
 	rootCmd = &cobra.Command{
@@ -20,6 +32,35 @@ var (
 func Execute() {
 	err := rootCmd.Execute()
 	if err != nil {
-		os.Exit(1)
+		os.Exit(exitCodeError)
 	}
 }
+
+func exitWithError(err error) {
+	log.Print(err)
+	os.Exit(getExitCode(err))
+}
+
+func logWarning(warning error) {
+	log.Printf("warning: %s", warning)
+}
+
+func getExitCode(err error) int {
+	var notFoundErr *config.NotFoundError
+	var parseErr *config.ParseError
+	var templateNotFoundErr *config.TemplateNotFoundError
+	var validationErr *config.ValidationError
+
+	switch {
+	case errors.As(err, &notFoundErr):
+		return exitCodeConfigNotFound
+	case errors.As(err, &parseErr):
+		return exitCodeConfigParse
+	case errors.As(err, &templateNotFoundErr):
+		return exitCodeTemplateNotFound
+	case errors.As(err, &validationErr):
+		return exitCodeValidation
+	}
+
+	return exitCodeError
+}
@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/drieschel/yddns/internal/config"
+	"github.com/drieschel/yddns/internal/systemd"
+	"github.com/drieschel/yddns/pkg/yddns"
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagMode        = "mode"
+	flagUser        = "user"
+	flagWatchdogSec = "watchdog-sec"
+
+	systemdSystemDir = "/etc/systemd/system"
+)
+
+var systemdCmd = &cobra.Command{
+	Use:   "systemd",
+	Short: "Integrate yddns with systemd",
+	Long:  `Integrate yddns with systemd`,
+}
+
+var systemdGenerateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate systemd unit files",
+	Long:  `Generate a hardened service unit refreshing periodically (mode service) or a oneshot service unit with a timer (mode timer)`,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		dir, _ := cmd.Flags().GetString(flagDir)
+		dryRun, _ := cmd.Flags().GetBool(flagDryRun)
+		force, _ := cmd.Flags().GetBool(flagForce)
+		mode, _ := cmd.Flags().GetString(flagMode)
+		user, _ := cmd.Flags().GetBool(flagUser)
+		watchdogSec, _ := cmd.Flags().GetInt(flagWatchdogSec)
+
+		cfg, err := config.NewOptionalFileConfig(version)
+		if err != nil {
+			exitWithError(err)
+		}
+
+		executable, err := os.Executable()
+		if err == nil {
+			executable, err = filepath.EvalSymlinks(executable)
+		}
+
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		units, err := systemd.GenerateUnits(&systemd.UnitOptions{ConfigFile: cfg.File, Executable: executable, Mode: mode, RefreshInterval: cfg.RefreshInterval, User: user, WatchdogSec: watchdogSec})
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if dryRun {
+			for _, unit := range units {
+				fmt.Printf("# %s\n%s\n", unit.Name, unit.Content)
+			}
+
+			return
+		}
+
+		if dir == "" {
+			dir, err = determineSystemdDir(user)
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
+
+		for _, unit := range units {
+			filePath := filepath.Join(dir, unit.Name)
+			err = writeFile(filePath, []byte(unit.Content), 0644, force)
+			if os.IsExist(err) {
+				log.Fatalf("unit file %s already exists (use --%s to overwrite it)", filePath, flagForce)
+			}
+
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			fmt.Printf("unit file %s created\n", filePath)
+		}
+
+		systemctl := "systemctl"
+		if user {
+			systemctl = "systemctl --user"
+		}
+
+		fmt.Printf("enable it with: %s daemon-reload && %s enable --now %s\n", systemctl, systemctl, units[len(units)-1].Name)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(systemdCmd)
+	systemdCmd.AddCommand(systemdGenerateCmd)
+
+	systemdGenerateCmd.Flags().StringP(flagConfigFile, "c", "", "Override default config using absolute file path")
+	systemdGenerateCmd.Flags().String(flagMode, systemd.ModeService, fmt.Sprintf("Set mode [%s]", strings.Join(systemd.SupportedModes, "|")))
+	systemdGenerateCmd.Flags().Bool(flagUser, false, "Generate user units instead of system units")
+	systemdGenerateCmd.Flags().Int(flagWatchdogSec, systemd.WatchdogSecDefault, "Set seconds without watchdog ping until systemd restarts the service, 0 disables the watchdog (mode service)")
+	systemdGenerateCmd.Flags().String(flagDir, "", fmt.Sprintf("Set directory of the unit files (default %s or the systemd user directory)", systemdSystemDir))
+	systemdGenerateCmd.Flags().Bool(flagForce, false, "Overwrite existing unit files")
+	systemdGenerateCmd.Flags().Bool(flagDryRun, false, "Print the unit files instead of writing them")
+	systemdGenerateCmd.Flags().SortFlags = false
+}
+
+func determineSystemdDir(user bool) (string, error) {
+	if !user {
+		return systemdSystemDir, nil
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "systemd", "user"), nil
+}
+
+func createSystemdEventHandler(notifier systemd.Notifier, watchdog *systemd.Watchdog) yddns.EventHandler {
+	return func(event *yddns.Event) {
+		var err error
+		switch event.Type {
+		case yddns.EventRefreshStarted:
+			watchdog.SetBusy(true)
+			err = watchdog.Ping()
+
+		case yddns.EventRefreshUpdated, yddns.EventRefreshSkipped, yddns.EventRefreshFailed:
+			watchdog.SetBusy(false)
+			err = watchdog.Ping()
+
+		case yddns.EventRefreshAllFinished:
+			err = notifier.Notify(systemd.Status(fmt.Sprintf("Last refresh at %s: %s", time.Now().Format(time.DateTime), summarizeRefreshResults(event.Results))))
+		}
+
+		if err != nil {
+			log.Printf("An error occurred when notifying systemd: %s\n", err)
+		}
+	}
+}
+
+func notifySystemd(notifier systemd.Notifier, states ...string) {
+	err := notifier.Notify(states...)
+	if err != nil {
+		log.Printf("An error occurred when notifying systemd: %s\n", err)
+	}
+}
+
+func summarizeRefreshResults(results []*yddns.Result) string {
+	counts := map[string]int{}
+	for _, result := range results {
+		action := result.Action
+		if result.HasFailed() {
+			action = yddns.ActionFailed
+		}
+
+		counts[action]++
+	}
+
+	return fmt.Sprintf("%d updated, %d skipped, %d failed", counts[yddns.ActionUpdated], counts[yddns.ActionSkipped], counts[yddns.ActionFailed])
+}
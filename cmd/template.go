@@ -0,0 +1,269 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"text/tabwriter"
+
+	"github.com/drieschel/yddns/internal/config"
+	"github.com/drieschel/yddns/pkg/yddns"
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/spf13/cobra"
+)
+
+const flagDir = "dir"
+
+var templateKeys = []string{config.KeyAuthMethod, config.KeyExtends, config.KeyHost, config.KeyProtocol, config.KeyRefreshUrl, config.KeyRefreshUrlIp4, config.KeyRefreshUrlIp6, config.KeyRequestMethod, config.KeyUserAgent}
+
+var templateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "List, show, test and create refresh url templates",
+	Long:  `List, show, test and create refresh url templates from the template directories and the config file`,
+}
+
+var templateListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all templates including overridden ones",
+	Long:  `List all templates with their source file, host and the source overriding them`,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString(flagOutput)
+		cfg, err := config.NewOptionalFileConfig(version)
+		if err != nil {
+			exitWithError(err)
+		}
+
+		err = printTemplateDefinitions(os.Stdout, cfg.ListTemplates(), output)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var templateShowCmd = &cobra.Command{
+	Use:   "show <name>",
+	Short: "Show a template",
+	Long:  `Show all values and the source file of a template`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString(flagOutput)
+		cfg, err := config.NewOptionalFileConfig(version)
+		if err != nil {
+			exitWithError(err)
+		}
+
+		template, err := cfg.GetTemplate(args[0])
+		if err != nil {
+			exitWithError(err)
+		}
+
+		values := createTemplateValues(template)
+		values[keySource] = cfg.TemplateSources[args[0]]
+
+		err = printValues(os.Stdout, values, append(slices.Clone(templateKeys), keySource), output)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var templateTestCmd = &cobra.Command{
+	Use:   "test <name>",
+	Short: "Render the refresh urls of a template with sample values",
+	Long:  `Render the refresh urls of a template with sample values, which can be overridden by flags`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString(flagOutput)
+		for _, flag := range []string{flagIp4Address, flagIp6Address} {
+			if address, _ := cmd.Flags().GetString(flag); address == "" {
+				log.Fatalf("the sample address --%s must not be empty", flag)
+			}
+		}
+
+		cfg, err := yddns.LoadOptionalConfig(version)
+		if err != nil {
+			exitWithError(err)
+		}
+
+		domain := createDomain(cmd, config.RefreshUrlTemplatePrefix+args[0])
+		domain.AddressPolicy = config.AddressPolicyAllow
+
+		requests, err := yddns.NewWithConfig(cfg, yddns.WithEventHandler(logWarningEvent)).BuildRequests(context.Background(), domain)
+		if err != nil {
+			exitWithError(err)
+		}
+
+		values := map[string]any{}
+		var keys []string
+		for _, request := range requests {
+			key := config.KeyRefreshUrl
+			if request.Family != "" {
+				key = fmt.Sprintf("%s_%s", config.KeyRefreshUrl, request.Family)
+			}
+
+			values[key] = request.URL.String()
+			keys = append(keys, key)
+		}
+
+		err = printValues(os.Stdout, values, keys, output)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var templateNewCmd = &cobra.Command{
+	Use:   "new <name>",
+	Short: "Create a new template file",
+	Long:  `Create a new template file in the templates directory`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dir, _ := cmd.Flags().GetString(flagDir)
+		extends, _ := cmd.Flags().GetString(config.KeyExtends)
+		refreshUrl, _ := cmd.Flags().GetString(createFlagName(config.KeyRefreshUrl))
+		domain := createDomain(cmd, refreshUrl)
+		template := &config.Template{
+			AuthMethod:    domain.AuthMethod,
+			Extends:       extends,
+			Host:          domain.Host,
+			Protocol:      domain.Protocol,
+			RefreshUrl:    domain.RefreshUrl,
+			RefreshUrlIp4: domain.RefreshUrlIp4,
+			RefreshUrlIp6: domain.RefreshUrlIp6,
+			RequestMethod: domain.RequestMethod,
+			UserAgent:     domain.UserAgent,
+		}
+
+		if template.Extends == "" && template.RefreshUrl == "" && template.RefreshUrlIp4 == "" && template.RefreshUrlIp6 == "" {
+			log.Fatalf("a refresh url or a template to extend is required (--%s, --%s, --%s or --%s)", createFlagName(config.KeyRefreshUrl), flagRefreshUrlIp4, flagRefreshUrlIp6, config.KeyExtends)
+		}
+
+		filePath := filepath.Join(dir, config.DirNameTemplates, args[0]+".json")
+		if _, err := os.Stat(filePath); err == nil {
+			log.Fatalf("template file %s already exists", filePath)
+		}
+
+		values := createTemplateValues(template)
+		for key, value := range values {
+			if value == "" {
+				delete(values, key)
+			}
+		}
+
+		err := os.MkdirAll(filepath.Dir(filePath), 0755)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		file, err := os.OpenFile(filePath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		defer file.Close()
+
+		encoder := json.NewEncoder(file)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+
+		err = encoder.Encode(values)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		fmt.Printf("template %s created in %s\n", args[0], filePath)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(templateCmd)
+	templateCmd.AddCommand(templateListCmd)
+	templateCmd.AddCommand(templateShowCmd)
+	templateCmd.AddCommand(templateTestCmd)
+	templateCmd.AddCommand(templateNewCmd)
+	templateCmd.PersistentFlags().StringP(flagConfigFile, "c", "", "Override default config using absolute file path")
+
+	for _, cmd := range []*cobra.Command{templateListCmd, templateShowCmd, templateTestCmd} {
+		cmd.Flags().StringP(flagOutput, "o", outputTable, fmt.Sprintf("Set output format [%s|%s]", outputTable, outputJson))
+	}
+
+	templateTestCmd.Flags().String(flagDomainName, "yddns.example.org", "Set sample domain")
+	templateTestCmd.Flags().String(flagUsername, "username", "Set sample username")
+	templateTestCmd.Flags().String(flagPassword, "password", "Set sample password")
+	templateTestCmd.Flags().String(flagIp4Address, "192.0.2.1", "Set sample IPv4 address")
+	templateTestCmd.Flags().String(flagIp6Address, "2001:db8::1", "Set sample IPv6 address")
+	templateTestCmd.Flags().String(flagHost, "", "Override host of the template")
+	templateTestCmd.Flags().SortFlags = false
+
+	templateNewCmd.Flags().String(config.KeyExtends, "", "Set name of the template to inherit unset values from")
+	templateNewCmd.Flags().String(createFlagName(config.KeyRefreshUrl), "", "Set refresh url with placeholders")
+	templateNewCmd.Flags().String(flagRefreshUrlIp4, "", "Set refresh url used for a separate IPv4 request")
+	templateNewCmd.Flags().String(flagRefreshUrlIp6, "", "Set refresh url used for a separate IPv6 request")
+	templateNewCmd.Flags().String(flagHost, "", "Set host name of the service")
+	templateNewCmd.Flags().String(flagProtocol, "", "Set protocol [http|https]")
+	templateNewCmd.Flags().String(flagAuthMethod, "", "Set authentication method [basic|bearer]")
+	templateNewCmd.Flags().String(flagRequestMethod, "", "Set request method [GET|POST]")
+	templateNewCmd.Flags().String(flagUserAgent, "", "Set user agent")
+	templateNewCmd.Flags().String(flagDir, config.Dirs[0], "Set directory containing the templates directory")
+	templateNewCmd.Flags().SortFlags = false
+}
+
+func createTemplateValues(template *config.Template) map[string]any {
+	values := map[string]any{}
+	_ = mapstructure.Decode(template, &values)
+
+	return values
+}
+
+func printTemplateDefinitions(w io.Writer, definitions []*config.TemplateDefinition, output string) error {
+	switch output {
+	case outputJson:
+		if definitions == nil {
+			definitions = []*config.TemplateDefinition{}
+		}
+
+		encoder := json.NewEncoder(w)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(definitions)
+
+	case outputTable:
+		writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "NAME\tSOURCE\tHOST\tEXTENDS\tOVERRIDDEN BY")
+		for _, definition := range definitions {
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n", definition.Name, definition.Source, definition.Template.Host, definition.Template.Extends, definition.OverriddenBy)
+		}
+
+		return writer.Flush()
+	}
+
+	return fmt.Errorf("unsupported output format \"%s\"", output)
+}
+
+func printValues(w io.Writer, values map[string]any, keys []string, output string) error {
+	switch output {
+	case outputJson:
+		encoder := json.NewEncoder(w)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(values)
+
+	case outputTable:
+		writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		for _, key := range keys {
+			fmt.Fprintf(writer, "%s\t%v\n", key, values[key])
+		}
+
+		return writer.Flush()
+	}
+
+	return fmt.Errorf("unsupported output format \"%s\"", output)
+}
@@ -88,6 +88,11 @@ func (c *FileCache) GetByFileName(fileName string) (*Item, error) {
 }
 
 func (c *FileCache) Set(item *Item) error {
+	err := os.MkdirAll(c.cacheDir, 0755)
+	if err != nil {
+		return err
+	}
+
 	now := time.Now()
 	item.Modified = &now
 
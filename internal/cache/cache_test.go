@@ -0,0 +1,30 @@
+package cache
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileCache_SetCreatesCacheDir(t *testing.T) {
+	c := NewFileCacheWithDefaultValues(filepath.Join(t.TempDir(), "cache", "yddns"))
+
+	err := c.Set(NewItem("a.tld", "1.1.1.1"))
+	assert.NoError(t, err)
+
+	item, err := c.Get("a.tld")
+	assert.NoError(t, err)
+	assert.Equal(t, "1.1.1.1", item.Value)
+	assert.True(t, c.IsValid(*item))
+}
+
+func TestFileCache_GetWithoutCacheDir(t *testing.T) {
+	c := NewFileCacheWithDefaultValues(filepath.Join(t.TempDir(), "missing"))
+
+	item, err := c.Get("a.tld")
+
+	assert.NoError(t, err)
+	assert.Nil(t, item.Value)
+	assert.False(t, c.IsValid(*item))
+}
@@ -1,207 +1,539 @@
 package client
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
+	"net/netip"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/drieschel/yddns/internal/cache"
 	"github.com/drieschel/yddns/internal/config"
+	"github.com/drieschel/yddns/internal/history"
+	"github.com/drieschel/yddns/internal/netaddr"
+	"github.com/drieschel/yddns/internal/resolver"
 )
 
 const (
 	IdentUrlIpv4 = "https://v4.ident.me"
 	IdentUrlIpv6 = "https://v6.ident.me"
+
+	VerifyIntervalDefault = 5 * time.Second
+
+	ActionFailed  = "failed"
+	ActionSkipped = "skipped"
+	ActionUpdated = "updated"
+
+	ReasonNotChanged = "configuration not changed"
+	ReasonResolved   = "dns already resolves to the current addresses"
 )
 
 type HttpClient interface {
 	Do(req *http.Request) (resp *http.Response, err error)
-	Get(url string) (resp *http.Response, err error)
 }
 
+type WarningHandler func(domain *config.Domain, warning error)
+
 type Client struct {
-	cache      cache.Cache
-	httpClient HttpClient
-	wanIp4     string
-	wanIp6     string
+	cache                 cache.Cache
+	history               history.History
+	httpClient            HttpClient
+	interfaceAddressTable netaddr.InterfaceAddressTable
+	ipSource              IpSource
+	neighborTable         netaddr.NeighborTable
+	resolver              resolver.Resolver
+	verifyInterval        time.Duration
+	warningHandler        WarningHandler
+	wanIp4                string
+	wanIp6                string
 }
 
-func NewClient(cache cache.Cache, httpClient HttpClient) *Client {
-	return &Client{cache: cache, httpClient: httpClient}
+func NewClient(cache cache.Cache, history history.History, resolver resolver.Resolver, httpClient HttpClient) *Client {
+	return NewClientWithIpSource(cache, history, resolver, httpClient, NewIdentIpSource(httpClient))
+}
+
+func NewClientWithIpSource(cache cache.Cache, history history.History, resolver resolver.Resolver, httpClient HttpClient, ipSource IpSource) *Client {
+	return &Client{cache: cache, history: history, httpClient: httpClient, interfaceAddressTable: netaddr.NewProcInterfaceAddressTable(), ipSource: ipSource, neighborTable: netaddr.NewIpNeighborTable(), resolver: resolver, verifyInterval: VerifyIntervalDefault}
+}
+
+func (c *Client) Refresh(ctx context.Context, domain *config.Domain) (*RefreshResult, error) {
+	start := time.Now()
+
+	var result *RefreshResult
+	var err error
+	if domain.HasFamilyRefreshUrls() {
+		result, err = c.refreshFamilies(ctx, domain)
+	} else {
+		result, err = c.refreshDomain(ctx, domain)
+	}
+
+	if result == nil {
+		result = createFailedResult(domain, err)
+	}
+
+	result.Duration = time.Since(start)
+
+	return result, err
 }
 
-func (c *Client) Refresh(domain *config.Domain) (string, error) {
+func (c *Client) refreshFamilies(ctx context.Context, domain *config.Domain) (*RefreshResult, error) {
+	result := &RefreshResult{Domain: domain.DomainName, Action: ActionSkipped, Addresses: map[string]string{}}
+	var errs []error
+	var addressErrs []error
+	familyDomains := domain.SplitByFamily()
+	for _, familyDomain := range familyDomains {
+		start := time.Now()
+		family := familyDomain.Families[0]
+		familyResult, err := c.refreshDomain(ctx, familyDomain)
+
+		var addressErr *AddressError
+		if errors.As(err, &addressErr) {
+			addressErrs = append(addressErrs, err)
+			familyResult = &RefreshResult{Domain: domain.DomainName, Action: ActionSkipped, Reason: err.Error()}
+			err = nil
+		}
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", family, err))
+			if familyResult == nil {
+				familyResult = createFailedResult(domain, err)
+			}
+		}
+
+		familyResult.Family = family
+		familyResult.Duration = time.Since(start)
+		if familyResult.Action == ActionUpdated {
+			result.Action = ActionUpdated
+		}
+
+		maps.Copy(result.Addresses, familyResult.Addresses)
+		result.Families = append(result.Families, familyResult)
+	}
+
+	if len(addressErrs) == len(familyDomains) {
+		return nil, errors.Join(addressErrs...)
+	}
+
+	err := errors.Join(errs...)
+	if len(errs)+len(addressErrs) == len(familyDomains) && result.Action != ActionUpdated {
+		result.Action = ActionFailed
+		result.Reason = errors.Join(append(errs, addressErrs...)...).Error()
+	}
+
+	return result, err
+}
+
+func (c *Client) refreshDomain(ctx context.Context, domain *config.Domain) (*RefreshResult, error) {
+	if domain.SkipIfResolved {
+		return c.refreshIfNotResolved(ctx, domain)
+	}
+
 	cacheKey := createCacheKey(domain)
 	cacheItem, err := c.cache.Get(cacheKey)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	responseString := "skipped refresh - configuration not changed"
+	result := &RefreshResult{Domain: domain.DomainName, Action: ActionSkipped, Reason: ReasonNotChanged}
 	if !c.cache.IsValid(*cacheItem) {
-		var url string
-		url, err = c.BuildRefreshUrl(domain)
+		var replacements *map[string]string
+		replacements, err = c.BuildReplacements(ctx, domain)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 
-		var request *http.Request
-		request, err = http.NewRequest(domain.RequestMethod, url, nil)
+		result, err = c.refresh(ctx, domain, replacements)
 		if err != nil {
-			return "", err
+			return result, err
 		}
+	}
 
-		switch domain.AuthMethod {
-		case config.AuthMethodBasic:
-			if domain.AuthUser != "" && domain.AuthPassword != "" {
-				request.SetBasicAuth(domain.AuthUser, domain.AuthPassword)
-			}
+	err = c.cache.Set(cacheItem)
+	if err != nil {
+		return result, err
+	}
 
-		case config.AuthMethodBearer:
-			if domain.AuthPassword != "" {
-				request.Header.Set("Authorization", fmt.Sprintf("Bearer %s", domain.AuthPassword))
-			}
-		}
+	return result, nil
+}
+
+func (c *Client) refreshIfNotResolved(ctx context.Context, domain *config.Domain) (*RefreshResult, error) {
+	replacements, err := c.BuildReplacements(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
 
-		request.Header.Set("User-Agent", domain.UserAgent)
+	addresses := extractAddresses(replacements)
+	if c.IsResolved(ctx, domain, addresses) {
+		return &RefreshResult{Domain: domain.DomainName, Action: ActionSkipped, Reason: ReasonResolved, Addresses: addresses}, nil
+	}
 
-		var response *http.Response
-		response, err = c.httpClient.Do(request)
-		if err != nil {
-			return "", err
+	return c.refresh(ctx, domain, replacements)
+}
+
+func (c *Client) refresh(ctx context.Context, domain *config.Domain, replacements *map[string]string) (*RefreshResult, error) {
+	addresses := extractAddresses(replacements)
+	status, body, err := c.sendRefreshRequest(ctx, domain, replacePlaceholders(domain.RefreshUrl, replacements))
+	if err == nil && domain.Verify {
+		err = c.Verify(ctx, domain, addresses)
+	}
+
+	result := &RefreshResult{Domain: domain.DomainName, Action: ActionUpdated, Addresses: addresses, HttpStatus: status, Body: body}
+	historyErr := c.recordHistory(domain, addresses, result.String(), err)
+	if err != nil {
+		result.Action = ActionFailed
+		result.Reason = err.Error()
+
+		return result, errors.Join(err, historyErr)
+	}
+
+	return result, historyErr
+}
+
+func (c *Client) BuildRefreshUrl(ctx context.Context, domain *config.Domain) (string, error) {
+	replacements, err := c.BuildReplacements(ctx, domain)
+	if err != nil {
+		return "", err
+	}
+
+	return replacePlaceholders(domain.RefreshUrl, replacements), nil
+}
+
+func (c *Client) BuildStaticRefreshUrl(domain *config.Domain) string {
+	replacements := createDomainReplacements(domain)
+	for _, family := range config.SupportedFamilies {
+		if !domain.HasFamily(family) {
+			replacements.Set(family, "")
+		} else if address := domain.GetStaticAddress(family); address != "" {
+			replacements.Set(family, address)
 		}
+	}
 
-		defer response.Body.Close()
+	return replacePlaceholders(domain.RefreshUrl, replacements.Build())
+}
 
-		var responseBody []byte
-		responseBody, err = io.ReadAll(response.Body)
-		if err != nil {
-			return "", err
+func (c *Client) BuildReplacements(ctx context.Context, domain *config.Domain) (*map[string]string, error) {
+	replacements := createDomainReplacements(domain)
+
+	var errs []error
+	determined := false
+	for _, family := range config.SupportedFamilies {
+		if !strings.Contains(domain.RefreshUrl, createReplaceKey(family)) {
+			continue
 		}
 
-		responseString = fmt.Sprintf("refreshed - provider responded: \"%s\"", strings.Trim(string(responseBody), " "))
-		if response.StatusCode > 204 {
-			if responseString == "" {
-				responseString = response.Status
+		address := ""
+		if domain.HasFamily(family) {
+			var err error
+			address, err = c.DetermineAddress(ctx, domain, family)
+			if err != nil {
+				errs = append(errs, &AddressError{Family: family, Err: err})
+			} else {
+				determined = true
 			}
-
-			return "", errors.New(fmt.Sprintf("%s", responseString))
 		}
+
+		replacements.Set(family, address)
 	}
 
-	err = c.cache.Set(cacheItem)
-	if err != nil {
-		return responseString, err
+	if len(errs) > 0 && !determined {
+		return nil, errors.Join(errs...)
 	}
 
-	return responseString, nil
+	for _, err := range errs {
+		c.warn(domain, fmt.Errorf("%w of domain \"%s\" - refreshing the remaining families", err, domain.DomainName))
+	}
+
+	return replacements.Build(), nil
 }
 
-func (c *Client) BuildRefreshUrl(domain *config.Domain) (string, error) {
-	replacements, err := c.BuildReplacements(domain)
+func (c *Client) DetermineAddress(ctx context.Context, domain *config.Domain, family string) (string, error) {
+	var address string
+	var err error
+	switch family {
+	case config.KeyIp4:
+		address, err = c.determineIp4(ctx, domain)
+	case config.KeyIp6:
+		address, err = c.determineIp6(ctx, domain)
+	default:
+		return "", fmt.Errorf("family \"%s\" is not supported", family)
+	}
+
 	if err != nil {
 		return "", err
 	}
 
-	url := domain.RefreshUrl
-	for search, replacement := range *replacements {
-		url = strings.Replace(url, search, replacement, -1)
+	warning, err := domain.ApplyAddressPolicy(family, address)
+	if err != nil {
+		return "", err
 	}
 
-	return url, nil
+	if warning != nil {
+		c.warn(domain, warning)
+	}
+
+	return address, nil
 }
 
-func (c *Client) BuildReplacements(domain *config.Domain) (*map[string]string, error) {
-	replacements := NewReplacements()
+func (c *Client) determineIp4(ctx context.Context, domain *config.Domain) (string, error) {
+	if domain.Ip4Address != "" {
+		return domain.Ip4Address, nil
+	}
 
-	replacements.
-		SetDefault(config.KeyProtocol, config.DefaultProtocol)
+	return c.DetermineWanIp4(ctx)
+}
 
-	replacements.
-		Set(config.KeyUsername, domain.AuthUser).
-		Set(config.KeyPassword, domain.AuthPassword).
-		Set(config.KeyDomainName, domain.DomainName).
-		Set(config.KeyHost, domain.Host).
-		Set(config.KeyProtocol, domain.Protocol)
+func (c *Client) determineIp6(ctx context.Context, domain *config.Domain) (string, error) {
+	if domain.Ip6Address != "" {
+		return domain.Ip6Address, nil
+	}
+
+	hostId, err := c.determineIp6HostId(domain)
+	if err != nil {
+		return "", err
+	}
 
-	ip4Key := createReplaceKey(config.KeyIp4)
-	if strings.Contains(domain.RefreshUrl, ip4Key) {
-		var err error
-		ip4 := domain.Ip4Address
+	if hostId == "" {
+		return c.determineSourceIp6(ctx, domain)
+	}
 
-		if ip4 == "" {
-			ip4, err = c.DetermineWanIp4()
-			if err != nil {
-				return nil, err
-			}
+	prefix := domain.Ip6Prefix
+	if prefix == "" {
+		prefix, err = c.determineSourceIp6(ctx, domain)
+		if err != nil {
+			return "", err
 		}
+	}
+
+	return netaddr.ComposeIp6(prefix, domain.GetIp6PrefixLength(), hostId)
+}
 
-		replacements.Set(config.KeyIp4, ip4)
+func (c *Client) determineSourceIp6(ctx context.Context, domain *config.Domain) (string, error) {
+	if domain.Ip6Interface == "" {
+		return c.DetermineWanIp6(ctx)
 	}
 
-	ip6Key := createReplaceKey(config.KeyIp6)
-	if strings.Contains(domain.RefreshUrl, ip6Key) {
-		var err error
-		ip6 := domain.Ip6Address
+	return c.DetermineInterfaceIp6(domain.Ip6Interface, domain.Ip6InterfacePrefer, domain.Ip6InterfaceSuffix)
+}
 
-		if ip6 == "" {
-			ip6, err = c.DetermineWanIp6()
-			if err != nil {
-				return nil, err
-			}
+func (c *Client) DetermineInterfaceIp6(device string, prefer string, suffix string) (string, error) {
+	addresses, err := c.interfaceAddressTable.InterfaceAddresses()
+	if err != nil {
+		return "", err
+	}
 
-			if domain.Ip6HostId != "" {
-				prefix := strings.Join(strings.Split(ip6, ":")[:4], ":")
-				ip6 = fmt.Sprintf("%s:%s", prefix, domain.Ip6HostId)
-			}
-		}
+	return netaddr.SelectIp6InterfaceAddress(addresses, device, prefer, suffix)
+}
 
-		replacements.Set(config.KeyIp6, ip6)
+func (c *Client) determineIp6HostId(domain *config.Domain) (string, error) {
+	if domain.Ip6Mac == "" {
+		return domain.Ip6HostId, nil
 	}
 
-	return replacements.Build(), nil
+	if !domain.Ip6NeighborLookup {
+		return netaddr.CreateEui64HostId(domain.Ip6Mac)
+	}
+
+	neighbors, err := c.neighborTable.Neighbors()
+	if err != nil {
+		return "", err
+	}
+
+	addresses, err := netaddr.FindGlobalNeighborAddresses(neighbors, domain.Ip6Mac)
+	if err != nil {
+		return "", err
+	}
+
+	if len(addresses) == 0 {
+		return "", fmt.Errorf("no global ipv6 neighbor address found for mac address \"%s\"", domain.Ip6Mac)
+	}
+
+	return addresses[0].String(), nil
 }
 
-func (c *Client) DetermineWanIp4() (string, error) {
+func (c *Client) DetermineWanIp4(ctx context.Context) (string, error) {
 	if c.wanIp4 == "" {
-		response, err := c.httpClient.Get(IdentUrlIpv4)
+		ip, err := c.ipSource.Address(ctx, config.KeyIp4)
 		if err != nil {
 			return "", err
 		}
 
-		defer response.Body.Close()
+		c.wanIp4 = ip
+	}
+
+	return c.wanIp4, nil
+}
 
-		ip, err := io.ReadAll(response.Body)
+func (c *Client) DetermineWanIp6(ctx context.Context) (string, error) {
+	if c.wanIp6 == "" {
+		ip, err := c.ipSource.Address(ctx, config.KeyIp6)
 		if err != nil {
 			return "", err
 		}
 
-		c.wanIp4 = string(ip)
+		c.wanIp6 = ip
 	}
 
-	return c.wanIp4, nil
+	return c.wanIp6, nil
 }
 
-func (c *Client) DetermineWanIp6() (string, error) {
-	if c.wanIp6 == "" {
-		response, err := c.httpClient.Get(IdentUrlIpv6)
+func (c *Client) BuildRefreshRequest(ctx context.Context, domain *config.Domain, url string) (*http.Request, error) {
+	request, err := http.NewRequestWithContext(ctx, domain.RequestMethod, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	switch domain.AuthMethod {
+	case config.AuthMethodBasic:
+		if domain.AuthUser != "" && domain.AuthPassword != "" {
+			request.SetBasicAuth(domain.AuthUser, domain.AuthPassword)
+		}
+
+	case config.AuthMethodBearer:
+		if domain.AuthPassword != "" {
+			request.Header.Set("Authorization", fmt.Sprintf("Bearer %s", domain.AuthPassword))
+		}
+	}
+
+	request.Header.Set("User-Agent", domain.UserAgent)
+
+	return request, nil
+}
+
+func (c *Client) sendRefreshRequest(ctx context.Context, domain *config.Domain, url string) (int, string, error) {
+	request, err := c.BuildRefreshRequest(ctx, domain, url)
+	if err != nil {
+		return 0, "", err
+	}
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return 0, "", err
+	}
+
+	defer response.Body.Close()
+
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return response.StatusCode, "", err
+	}
+
+	body := strings.Trim(string(responseBody), " ")
+	if response.StatusCode > 204 {
+		return response.StatusCode, body, fmt.Errorf("refreshed - provider responded: \"%s\"", body)
+	}
+
+	return response.StatusCode, body, nil
+}
+
+func (c *Client) IsResolved(ctx context.Context, domain *config.Domain, addresses map[string]string) bool {
+	if len(addresses) == 0 {
+		return false
+	}
+
+	for family, address := range addresses {
+		resolved, err := c.resolver.Lookup(ctx, domain.Resolver, domain.DomainName, family)
+		if err != nil || len(resolved) != 1 || !containsAddress(resolved, address) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (c *Client) Verify(ctx context.Context, domain *config.Domain, addresses map[string]string) error {
+	deadline := time.Now().Add(time.Duration(domain.VerifyTimeout) * time.Second)
+	for {
+		err := c.verifyAddresses(ctx, domain, addresses)
+		if err == nil || time.Now().Add(c.verifyInterval).After(deadline) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return errors.Join(err, ctx.Err())
+		case <-time.After(c.verifyInterval):
+		}
+	}
+}
+
+func (c *Client) verifyAddresses(ctx context.Context, domain *config.Domain, addresses map[string]string) error {
+	for _, family := range []string{config.KeyIp4, config.KeyIp6} {
+		address, ok := addresses[family]
+		if !ok {
+			continue
+		}
+
+		resolved, err := c.resolver.Lookup(ctx, domain.Resolver, domain.DomainName, family)
 		if err != nil {
-			return "", err
+			return &VerificationError{Domain: domain.DomainName, Family: family, Expected: address, Err: err}
+		}
+
+		if !containsAddress(resolved, address) {
+			return &VerificationError{Domain: domain.DomainName, Family: family, Expected: address, Resolved: resolved}
 		}
+	}
+
+	return nil
+}
 
-		defer response.Body.Close()
+func (c *Client) recordHistory(domain *config.Domain, addresses map[string]string, responseString string, refreshErr error) error {
+	result := history.ResultUpdated
+	message := responseString
+	if refreshErr != nil {
+		result = history.ResultFailed
+		message = refreshErr.Error()
 
-		ip, err := io.ReadAll(response.Body)
+		var verificationErr *VerificationError
+		if errors.As(refreshErr, &verificationErr) {
+			result = history.ResultUnverified
+		}
+	}
+
+	var errs []error
+	recorded := false
+	for _, family := range []string{config.KeyIp4, config.KeyIp6} {
+		address, ok := addresses[family]
+		if !ok {
+			continue
+		}
+
+		oldAddress := ""
+		lastEntry, err := c.history.LastUpdated(domain.DomainName, family)
 		if err != nil {
-			return "", err
+			errs = append(errs, err)
+		} else if lastEntry != nil {
+			oldAddress = lastEntry.NewAddress
 		}
 
-		c.wanIp6 = string(ip)
+		err = c.history.Add(history.NewEntry(domain.DomainName, family, oldAddress, address, result, message))
+		if err != nil {
+			errs = append(errs, err)
+		}
+
+		recorded = true
 	}
 
-	return c.wanIp6, nil
+	if !recorded {
+		return c.history.Add(history.NewEntry(domain.DomainName, "", "", "", result, message))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c *Client) SetWarningHandler(handler WarningHandler) {
+	c.warningHandler = handler
+}
+
+func (c *Client) warn(domain *config.Domain, warning error) {
+	if c.warningHandler != nil {
+		c.warningHandler(domain, warning)
+	}
 }
 
 func (c *Client) Clear() {
@@ -209,6 +541,94 @@ func (c *Client) Clear() {
 	c.wanIp6 = ""
 }
 
+type RefreshResult struct {
+	Domain     string            `json:"domain"`
+	Family     string            `json:"family,omitempty"`
+	Action     string            `json:"action"`
+	Reason     string            `json:"reason,omitempty"`
+	Addresses  map[string]string `json:"addresses,omitempty"`
+	HttpStatus int               `json:"http_status,omitempty"`
+	Body       string            `json:"body,omitempty"`
+	Duration   time.Duration     `json:"-"`
+	Families   []*RefreshResult  `json:"families,omitempty"`
+}
+
+func (r *RefreshResult) MarshalJSON() ([]byte, error) {
+	type result RefreshResult
+
+	return json.Marshal(&struct {
+		*result
+		DurationMs int64 `json:"duration_ms"`
+	}{result: (*result)(r), DurationMs: r.Duration.Milliseconds()})
+}
+
+func (r *RefreshResult) HasFailed() bool {
+	if r.Action == ActionFailed {
+		return true
+	}
+
+	for _, familyResult := range r.Families {
+		if familyResult.HasFailed() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *RefreshResult) String() string {
+	if len(r.Families) > 0 {
+		var messages []string
+		for _, familyResult := range r.Families {
+			messages = append(messages, fmt.Sprintf("%s: %s", familyResult.Family, familyResult))
+		}
+
+		return strings.Join(messages, "; ")
+	}
+
+	switch r.Action {
+	case ActionSkipped:
+		return fmt.Sprintf("skipped refresh - %s", r.Reason)
+	case ActionFailed:
+		return fmt.Sprintf("refresh failed - %s", r.Reason)
+	}
+
+	return fmt.Sprintf("refreshed - provider responded: \"%s\"", r.Body)
+}
+
+type AddressError struct {
+	Family string
+	Err    error
+}
+
+func (e *AddressError) Error() string {
+	return fmt.Sprintf("%s address could not be determined: %s", e.Family, e.Err)
+}
+
+func (e *AddressError) Unwrap() error {
+	return e.Err
+}
+
+type VerificationError struct {
+	Domain   string
+	Family   string
+	Expected string
+	Resolved []string
+	Err      error
+}
+
+func (e *VerificationError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("verification failed - %s record of %s could not be resolved: %s", e.Family, e.Domain, e.Err)
+	}
+
+	return fmt.Sprintf("verification failed - %s record of %s resolves to [%s], expected %s", e.Family, e.Domain, strings.Join(e.Resolved, ", "), e.Expected)
+}
+
+func (e *VerificationError) Unwrap() error {
+	return e.Err
+}
+
 type Replacements struct {
 	defaults map[string]string
 	items    map[string]string
@@ -243,6 +663,72 @@ func (r *Replacements) SetDefault(key string, value string) *Replacements {
 	return r
 }
 
+func extractAddresses(replacements *map[string]string) map[string]string {
+	addresses := map[string]string{}
+	for _, family := range []string{config.KeyIp4, config.KeyIp6} {
+		if address, ok := (*replacements)[createReplaceKey(family)]; ok && address != "" {
+			addresses[family] = address
+		}
+	}
+
+	return addresses
+}
+
+func containsAddress(addresses []string, address string) bool {
+	expected, err := netip.ParseAddr(address)
+	if err != nil {
+		return slices.Contains(addresses, address)
+	}
+
+	for _, a := range addresses {
+		if actual, err := netip.ParseAddr(a); err == nil && actual.Unmap() == expected.Unmap() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func createFailedResult(domain *config.Domain, err error) *RefreshResult {
+	result := &RefreshResult{Domain: domain.DomainName, Action: ActionFailed}
+	if err != nil {
+		result.Reason = err.Error()
+	}
+
+	return result
+}
+
+func createDomainReplacements(domain *config.Domain) *Replacements {
+	replacements := NewReplacements()
+
+	replacements.
+		SetDefault(config.KeyProtocol, config.DefaultProtocol)
+
+	replacements.
+		Set(config.KeyUsername, domain.AuthUser).
+		Set(config.KeyPassword, domain.AuthPassword).
+		Set(config.KeyDomainName, domain.DomainName).
+		Set(config.KeyHost, domain.Host).
+		Set(config.KeyProtocol, domain.Protocol)
+
+	return replacements
+}
+
+func replacePlaceholders(url string, replacements *map[string]string) string {
+	for _, family := range config.SupportedFamilies {
+		key := createReplaceKey(family)
+		if address, ok := (*replacements)[key]; ok && address == "" {
+			url = strings.NewReplacer(","+key, "", key+",", "").Replace(url)
+		}
+	}
+
+	for search, replacement := range *replacements {
+		url = strings.Replace(url, search, replacement, -1)
+	}
+
+	return url
+}
+
 func createReplaceKey(key string) string {
 	return fmt.Sprintf("<%s>", key)
 }
@@ -2,16 +2,24 @@ package client
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/netip"
 	"testing"
 	"time"
 
 	"github.com/drieschel/yddns/internal/cache"
 	"github.com/drieschel/yddns/internal/config"
+	"github.com/drieschel/yddns/internal/history"
+	"github.com/drieschel/yddns/internal/netaddr"
+	"github.com/drieschel/yddns/internal/resolver"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestClient_RefreshWithoutCache(t *testing.T) {
@@ -27,17 +35,24 @@ func TestClient_RefreshWithoutCache(t *testing.T) {
 			cacheMock.EXPECT().Get(test.expectedCacheKey).Return(cacheItem, nil).Once()
 			cacheMock.EXPECT().Set(cacheItem).Return(nil).Once()
 
+			historyMock := history.NewMockHistory(t)
+			historyMock.EXPECT().Add(mock.MatchedBy(func(entry *history.Entry) bool {
+				return entry.Result == history.ResultUpdated && entry.Family == "" && entry.Message == expectedResponse
+			})).Return(nil).Once()
+
 			request := createHttpRequest(test.domain)
 			response := createHttpResponse(providerResponse)
 			httpClientMock := NewMockHttpClient(t)
 			httpClientMock.EXPECT().Do(request).Return(response, nil).Once()
 
-			client := *NewClient(cacheMock, httpClientMock)
+			client := *NewClient(cacheMock, historyMock, resolver.NewMockResolver(t), httpClientMock)
 
-			actualResponse, err := client.Refresh(&test.domain)
+			actualResult, err := client.Refresh(context.Background(), &test.domain)
 
 			assert.NoError(t, err)
-			assert.Equal(t, expectedResponse, actualResponse)
+			assert.Equal(t, ActionUpdated, actualResult.Action)
+			assert.Equal(t, providerResponse, actualResult.Body)
+			assert.Equal(t, expectedResponse, actualResult.String())
 		})
 	}
 }
@@ -56,18 +71,284 @@ func TestClient_RefreshWithValidCacheItem(t *testing.T) {
 			cacheMock.EXPECT().Get(test.expectedCacheKey).Return(cacheItem, nil).Once()
 			cacheMock.EXPECT().Set(cacheItem).Return(nil).Once()
 
+			historyMock := history.NewMockHistory(t)
 			httpClientMock := NewMockHttpClient(t)
 
-			client := *NewClient(cacheMock, httpClientMock)
+			client := *NewClient(cacheMock, historyMock, resolver.NewMockResolver(t), httpClientMock)
 
-			actualResponse, err := client.Refresh(&test.domain)
+			actualResult, err := client.Refresh(context.Background(), &test.domain)
 
 			assert.NoError(t, err)
-			assert.Equal(t, expectedResponse, actualResponse)
+			assert.Equal(t, &RefreshResult{Domain: test.domain.DomainName, Action: ActionSkipped, Reason: ReasonNotChanged, Duration: actualResult.Duration}, actualResult)
+			assert.Equal(t, expectedResponse, actualResult.String())
 		})
 	}
 }
 
+func TestClient_RefreshRecordsHistory(t *testing.T) {
+	domain := config.Domain{DomainName: "yddns.drieschel.org", Ip6Address: "2001:db8::1", Template: config.Template{RefreshUrl: "https://fancy-dyn.dns?e=<ip4>&f=<ip6>", UserAgent: "test"}}
+	cacheItem := cache.NewItem(createCacheKey(&domain), nil)
+
+	cacheMock := cache.NewMockCache(t)
+	cacheMock.EXPECT().Get(cacheItem.Key).Return(cacheItem, nil).Once()
+	cacheMock.EXPECT().IsValid(*cacheItem).Return(false).Once()
+	cacheMock.EXPECT().Set(cacheItem).Return(nil).Once()
+
+	historyMock := history.NewMockHistory(t)
+	historyMock.EXPECT().LastUpdated("yddns.drieschel.org", "ip4").Return(&history.Entry{NewAddress: "125.148.255.40"}, nil).Once()
+	historyMock.EXPECT().LastUpdated("yddns.drieschel.org", "ip6").Return(nil, nil).Once()
+	historyMock.EXPECT().Add(mock.MatchedBy(func(entry *history.Entry) bool {
+		return entry.Family == "ip4" && entry.OldAddress == "125.148.255.40" && entry.NewAddress == "125.148.255.41" && entry.Result == history.ResultUpdated
+	})).Return(nil).Once()
+	historyMock.EXPECT().Add(mock.MatchedBy(func(entry *history.Entry) bool {
+		return entry.Family == "ip6" && entry.OldAddress == "" && entry.NewAddress == "2001:db8::1" && entry.Result == history.ResultUpdated
+	})).Return(nil).Once()
+
+	httpClientMock := NewMockHttpClient(t)
+	httpClientMock.EXPECT().Do(matchRequestUrl(IdentUrlIpv4)).Return(createHttpResponse("125.148.255.41"), nil).Once()
+	httpClientMock.EXPECT().Do(mock.Anything).Return(createHttpResponse("good"), nil).Once()
+
+	client := NewClient(cacheMock, historyMock, resolver.NewMockResolver(t), httpClientMock)
+
+	_, err := client.Refresh(context.Background(), &domain)
+
+	assert.NoError(t, err)
+}
+
+func TestClient_RefreshWithFailedVerification(t *testing.T) {
+	domain := config.Domain{DomainName: "yddns.drieschel.org", Ip4Address: "125.148.255.41", Resolver: "127.0.0.1:53", Verify: true, Template: config.Template{RefreshUrl: "https://fancy-dyn.dns?e=<ip4>", UserAgent: "test"}}
+	cacheItem := cache.NewItem(createCacheKey(&domain), nil)
+
+	cacheMock := cache.NewMockCache(t)
+	cacheMock.EXPECT().Get(cacheItem.Key).Return(cacheItem, nil).Once()
+	cacheMock.EXPECT().IsValid(*cacheItem).Return(false).Once()
+
+	historyMock := history.NewMockHistory(t)
+	historyMock.EXPECT().LastUpdated("yddns.drieschel.org", "ip4").Return(nil, nil).Once()
+	historyMock.EXPECT().Add(mock.MatchedBy(func(entry *history.Entry) bool {
+		return entry.Family == "ip4" && entry.NewAddress == "125.148.255.41" && entry.Result == history.ResultUnverified
+	})).Return(nil).Once()
+
+	resolverMock := resolver.NewMockResolver(t)
+	resolverMock.EXPECT().Lookup(mock.Anything, "127.0.0.1:53", "yddns.drieschel.org", "ip4").Return([]string{"125.148.255.40"}, nil).Once()
+
+	httpClientMock := NewMockHttpClient(t)
+	httpClientMock.EXPECT().Do(mock.Anything).Return(createHttpResponse("good"), nil).Once()
+
+	client := NewClient(cacheMock, historyMock, resolverMock, httpClientMock)
+
+	actualResult, err := client.Refresh(context.Background(), &domain)
+
+	var verificationErr *VerificationError
+	assert.ErrorAs(t, err, &verificationErr)
+	assert.Equal(t, []string{"125.148.255.40"}, verificationErr.Resolved)
+	assert.Equal(t, ActionFailed, actualResult.Action)
+	assert.Equal(t, 200, actualResult.HttpStatus)
+	assert.Equal(t, "good", actualResult.Body)
+	assert.Equal(t, verificationErr.Error(), actualResult.Reason)
+}
+
+func TestClient_RefreshWithProviderError(t *testing.T) {
+	domain := config.Domain{DomainName: "yddns.drieschel.org", Ip4Address: "125.148.255.41", Template: config.Template{RefreshUrl: "https://fancy-dyn.dns?e=<ip4>", UserAgent: "test"}}
+	cacheItem := cache.NewItem(createCacheKey(&domain), nil)
+
+	cacheMock := cache.NewMockCache(t)
+	cacheMock.EXPECT().Get(cacheItem.Key).Return(cacheItem, nil).Once()
+	cacheMock.EXPECT().IsValid(*cacheItem).Return(false).Once()
+
+	historyMock := history.NewMockHistory(t)
+	historyMock.EXPECT().LastUpdated("yddns.drieschel.org", "ip4").Return(nil, nil).Once()
+	historyMock.EXPECT().Add(mock.MatchedBy(func(entry *history.Entry) bool {
+		return entry.Result == history.ResultFailed && entry.Message == "refreshed - provider responded: \"badauth\""
+	})).Return(nil).Once()
+
+	response := createHttpResponse("badauth")
+	response.StatusCode = 401
+	httpClientMock := NewMockHttpClient(t)
+	httpClientMock.EXPECT().Do(mock.Anything).Return(response, nil).Once()
+
+	client := NewClient(cacheMock, historyMock, resolver.NewMockResolver(t), httpClientMock)
+
+	actualResult, err := client.Refresh(context.Background(), &domain)
+
+	assert.EqualError(t, err, "refreshed - provider responded: \"badauth\"")
+	assert.Equal(t, &RefreshResult{Domain: "yddns.drieschel.org", Action: ActionFailed, Reason: err.Error(), Addresses: map[string]string{"ip4": "125.148.255.41"}, HttpStatus: 401, Body: "badauth", Duration: actualResult.Duration}, actualResult)
+	assert.Equal(t, "refresh failed - refreshed - provider responded: \"badauth\"", actualResult.String())
+}
+
+func TestClient_RefreshSkippedIfResolved(t *testing.T) {
+	domain := config.Domain{DomainName: "yddns.drieschel.org", SkipIfResolved: true, Template: config.Template{RefreshUrl: "https://fancy-dyn.dns?e=<ip4>&f=<ip6>"}}
+
+	resolverMock := resolver.NewMockResolver(t)
+	resolverMock.EXPECT().Lookup(mock.Anything, "", "yddns.drieschel.org", "ip4").Return([]string{"125.148.255.41"}, nil).Once()
+	resolverMock.EXPECT().Lookup(mock.Anything, "", "yddns.drieschel.org", "ip6").Return([]string{"2001:db8::1"}, nil).Once()
+
+	httpClientMock := NewMockHttpClient(t)
+	httpClientMock.EXPECT().Do(matchRequestUrl(IdentUrlIpv4)).Return(createHttpResponse("125.148.255.41"), nil).Once()
+	httpClientMock.EXPECT().Do(matchRequestUrl(IdentUrlIpv6)).Return(createHttpResponse("2001:db8::1"), nil).Once()
+
+	client := NewClient(cache.NewMockCache(t), history.NewMockHistory(t), resolverMock, httpClientMock)
+
+	actualResult, err := client.Refresh(context.Background(), &domain)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &RefreshResult{Domain: "yddns.drieschel.org", Action: ActionSkipped, Reason: ReasonResolved, Addresses: map[string]string{"ip4": "125.148.255.41", "ip6": "2001:db8::1"}, Duration: actualResult.Duration}, actualResult)
+	assert.Equal(t, "skipped refresh - dns already resolves to the current addresses", actualResult.String())
+}
+
+func TestClient_RefreshNotSkippedIfNotResolved(t *testing.T) {
+	domain := config.Domain{DomainName: "yddns.drieschel.org", Ip4Address: "125.148.255.41", SkipIfResolved: true, Template: config.Template{RefreshUrl: "https://fancy-dyn.dns?e=<ip4>", UserAgent: "test"}}
+
+	historyMock := history.NewMockHistory(t)
+	historyMock.EXPECT().LastUpdated("yddns.drieschel.org", "ip4").Return(nil, nil).Once()
+	historyMock.EXPECT().Add(mock.Anything).Return(nil).Once()
+
+	resolverMock := resolver.NewMockResolver(t)
+	resolverMock.EXPECT().Lookup(mock.Anything, "", "yddns.drieschel.org", "ip4").Return([]string{"125.148.255.40"}, nil).Once()
+
+	httpClientMock := NewMockHttpClient(t)
+	httpClientMock.EXPECT().Do(mock.Anything).Return(createHttpResponse("good"), nil).Once()
+
+	client := NewClient(cache.NewMockCache(t), historyMock, resolverMock, httpClientMock)
+
+	actualResult, err := client.Refresh(context.Background(), &domain)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &RefreshResult{Domain: "yddns.drieschel.org", Action: ActionUpdated, Addresses: map[string]string{"ip4": "125.148.255.41"}, HttpStatus: 200, Body: "good", Duration: actualResult.Duration}, actualResult)
+}
+
+func TestClient_RefreshPerFamily(t *testing.T) {
+	domain := config.Domain{DomainName: "yddns.drieschel.org", Template: config.Template{RefreshUrlIp4: "https://fancy-dyn.dns?myip=<ip4>", RefreshUrlIp6: "https://fancy-dyn.dns?myip=<ip6>", UserAgent: "test"}}
+
+	cacheMock := cache.NewMockCache(t)
+	cacheMock.EXPECT().Get(mock.Anything).RunAndReturn(func(key string) (*cache.Item, error) {
+		return cache.NewItem(key, nil), nil
+	}).Twice()
+	cacheMock.EXPECT().IsValid(mock.Anything).Return(false).Twice()
+	cacheMock.EXPECT().Set(mock.Anything).Return(nil).Once()
+
+	historyMock := history.NewMockHistory(t)
+	historyMock.EXPECT().LastUpdated("yddns.drieschel.org", "ip4").Return(nil, nil).Once()
+	historyMock.EXPECT().Add(mock.MatchedBy(func(entry *history.Entry) bool {
+		return entry.Family == "ip4" && entry.NewAddress == "125.148.255.41"
+	})).Return(nil).Once()
+
+	httpClientMock := NewMockHttpClient(t)
+	httpClientMock.EXPECT().Do(matchRequestUrl(IdentUrlIpv4)).Return(createHttpResponse("125.148.255.41"), nil).Once()
+	httpClientMock.EXPECT().Do(matchRequestUrl(IdentUrlIpv6)).Return(nil, errors.New("network is unreachable")).Once()
+	httpClientMock.EXPECT().Do(mock.MatchedBy(func(request *http.Request) bool {
+		return request.URL.String() == "https://fancy-dyn.dns?myip=125.148.255.41"
+	})).Return(createHttpResponse("good"), nil).Once()
+
+	client := NewClient(cacheMock, historyMock, resolver.NewMockResolver(t), httpClientMock)
+
+	actualResult, err := client.Refresh(context.Background(), &domain)
+
+	assert.NoError(t, err)
+	assert.Equal(t, ActionUpdated, actualResult.Action)
+	assert.Equal(t, map[string]string{"ip4": "125.148.255.41"}, actualResult.Addresses)
+	assert.Len(t, actualResult.Families, 2)
+	assert.Equal(t, "ip4: refreshed - provider responded: \"good\"; ip6: skipped refresh - ip6 address could not be determined: network is unreachable", actualResult.String())
+}
+
+func TestClient_RefreshPerFamilyWithoutAnyAddress(t *testing.T) {
+	domain := config.Domain{DomainName: "yddns.drieschel.org", Families: []string{"ip6"}, Template: config.Template{RefreshUrlIp4: "https://fancy-dyn.dns?myip=<ip4>", RefreshUrlIp6: "https://fancy-dyn.dns?myip=<ip6>"}}
+
+	cacheMock := cache.NewMockCache(t)
+	cacheMock.EXPECT().Get(mock.Anything).RunAndReturn(func(key string) (*cache.Item, error) {
+		return cache.NewItem(key, nil), nil
+	}).Once()
+	cacheMock.EXPECT().IsValid(mock.Anything).Return(false).Once()
+
+	httpClientMock := NewMockHttpClient(t)
+	httpClientMock.EXPECT().Do(matchRequestUrl(IdentUrlIpv6)).Return(nil, errors.New("network is unreachable")).Once()
+
+	client := NewClient(cacheMock, history.NewMockHistory(t), resolver.NewMockResolver(t), httpClientMock)
+
+	actualResult, err := client.Refresh(context.Background(), &domain)
+
+	var addressErr *AddressError
+	assert.ErrorAs(t, err, &addressErr)
+	assert.Equal(t, "ip6", addressErr.Family)
+	assert.Equal(t, ActionFailed, actualResult.Action)
+	assert.Equal(t, err.Error(), actualResult.Reason)
+}
+
+func TestRefreshResult_HasFailed(t *testing.T) {
+	tests := []struct {
+		result   *RefreshResult
+		expected bool
+	}{
+		{result: &RefreshResult{Action: ActionUpdated}, expected: false},
+		{result: &RefreshResult{Action: ActionSkipped}, expected: false},
+		{result: &RefreshResult{Action: ActionFailed}, expected: true},
+		{result: &RefreshResult{Action: ActionUpdated, Families: []*RefreshResult{{Family: "ip4", Action: ActionUpdated}, {Family: "ip6", Action: ActionFailed}}}, expected: true},
+		{result: &RefreshResult{Action: ActionUpdated, Families: []*RefreshResult{{Family: "ip4", Action: ActionUpdated}, {Family: "ip6", Action: ActionSkipped}}}, expected: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.result.Action, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.result.HasFailed())
+		})
+	}
+}
+
+func TestRefreshResult_MarshalJSON(t *testing.T) {
+	result := &RefreshResult{Domain: "yddns.drieschel.org", Action: ActionUpdated, Addresses: map[string]string{"ip4": "125.148.255.41"}, HttpStatus: 200, Body: "good", Duration: 1500 * time.Millisecond}
+
+	actualJson, err := json.Marshal(result)
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"domain":"yddns.drieschel.org","action":"updated","addresses":{"ip4":"125.148.255.41"},"http_status":200,"body":"good","duration_ms":1500}`, string(actualJson))
+}
+
+func TestClient_BuildReplacementsWithMissingFamily(t *testing.T) {
+	domain := config.Domain{Template: config.Template{RefreshUrl: "https://fancy-dyn.dns?myip=<ip4>,<ip6>"}}
+
+	httpClientMock := NewMockHttpClient(t)
+	httpClientMock.EXPECT().Do(matchRequestUrl(IdentUrlIpv4)).Return(createHttpResponse("125.148.255.41"), nil).Once()
+	httpClientMock.EXPECT().Do(matchRequestUrl(IdentUrlIpv6)).Return(nil, errors.New("network is unreachable")).Once()
+
+	client := NewClient(cache.NewMockCache(t), history.NewMockHistory(t), resolver.NewMockResolver(t), httpClientMock)
+
+	replacements, err := client.BuildReplacements(context.Background(), &domain)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "125.148.255.41", (*replacements)["<ip4>"])
+	assert.Equal(t, "", (*replacements)["<ip6>"])
+}
+
+func TestClient_Verify(t *testing.T) {
+	domain := config.Domain{DomainName: "yddns.drieschel.org", VerifyTimeout: 1}
+	addresses := map[string]string{"ip4": "125.148.255.41", "ip6": "2001:db8:0:0::1"}
+
+	resolverMock := resolver.NewMockResolver(t)
+	resolverMock.EXPECT().Lookup(mock.Anything, "", "yddns.drieschel.org", "ip4").Return([]string{}, nil).Once()
+	resolverMock.EXPECT().Lookup(mock.Anything, "", "yddns.drieschel.org", "ip4").Return([]string{"125.148.255.41"}, nil).Once()
+	resolverMock.EXPECT().Lookup(mock.Anything, "", "yddns.drieschel.org", "ip6").Return([]string{"2001:db8::1"}, nil).Once()
+
+	client := Client{resolver: resolverMock, verifyInterval: 10 * time.Millisecond}
+
+	assert.NoError(t, client.Verify(context.Background(), &domain, addresses))
+}
+
+func TestClient_VerifyTimeout(t *testing.T) {
+	domain := config.Domain{DomainName: "yddns.drieschel.org", VerifyTimeout: 0}
+	addresses := map[string]string{"ip6": "2001:db8::1"}
+
+	resolverMock := resolver.NewMockResolver(t)
+	resolverMock.EXPECT().Lookup(mock.Anything, "", "yddns.drieschel.org", "ip6").Return(nil, errors.New("timeout")).Once()
+
+	client := Client{resolver: resolverMock, verifyInterval: 10 * time.Millisecond}
+
+	err := client.Verify(context.Background(), &domain, addresses)
+
+	var verificationErr *VerificationError
+	assert.ErrorAs(t, err, &verificationErr)
+	assert.Equal(t, "ip6", verificationErr.Family)
+	assert.EqualError(t, err, "verification failed - ip6 record of yddns.drieschel.org could not be resolved: timeout")
+}
+
 func refreshTable() []struct {
 	name             string
 	domain           config.Domain
@@ -81,27 +362,27 @@ func refreshTable() []struct {
 		{
 			name:             "basic auth",
 			domain:           config.Domain{Template: config.Template{RefreshUrl: "abcd", AuthMethod: "basic", UserAgent: "test"}, AuthUser: "test", AuthPassword: "pass"},
-			expectedCacheKey: "be5d97fbba99da6caa52e066aab18708",
+			expectedCacheKey: "4a8decf9be62c2058a03c35d934607ac",
 		},
 		{
 			name:             "basic no auth",
 			domain:           config.Domain{Template: config.Template{RefreshUrl: "efgh", AuthMethod: "basic", UserAgent: "test"}, AuthUser: "", AuthPassword: ""},
-			expectedCacheKey: "d6e0d8f7e3ea92041a999aa36594b3b4",
+			expectedCacheKey: "0c447a82a34dab35787875488d1ddd0f",
 		},
 		{
 			name:             "bearer auth",
 			domain:           config.Domain{Template: config.Template{RefreshUrl: "ijkl", AuthMethod: "bearer", UserAgent: "test"}, AuthUser: "not used", AuthPassword: "token"},
-			expectedCacheKey: "2d95adbb807d114002059a79d14dc501",
+			expectedCacheKey: "7bb8b3f9346c036eec84c6c69beefa56",
 		},
 		{
 			name:             "bearer no auth",
 			domain:           config.Domain{Template: config.Template{RefreshUrl: "mnop", AuthMethod: "bearer", UserAgent: "test"}, AuthUser: "not used", AuthPassword: ""},
-			expectedCacheKey: "d5ebe2451342aac0ecea8f1349d0e9fa",
+			expectedCacheKey: "f306d2b2725245d00b335ce3ee975489",
 		},
 		{
 			name:             "no auth",
 			domain:           config.Domain{Template: config.Template{RefreshUrl: "qrst", AuthMethod: "", UserAgent: "test"}, AuthUser: "test", AuthPassword: "foo"},
-			expectedCacheKey: "4ed696325abba4fdad4c206ebf609981",
+			expectedCacheKey: "4ea579bd1285244bfc4042c88a361209",
 		},
 	}
 }
@@ -110,18 +391,19 @@ func TestClient_BuildRefreshUrl(t *testing.T) {
 	for _, data := range refreshUrlTable() {
 		t.Run(data.name, func(t *testing.T) {
 			cacheMock := cache.NewMockCache(t)
+			historyMock := history.NewMockHistory(t)
 			httpClient := NewMockHttpClient(t)
-			client := NewClient(cacheMock, httpClient)
+			client := NewClient(cacheMock, historyMock, resolver.NewMockResolver(t), httpClient)
 
 			if data.wanIp4 != "" {
-				httpClient.EXPECT().Get(IdentUrlIpv4).Return(createHttpResponse(data.wanIp4), nil).Once()
+				httpClient.EXPECT().Do(matchRequestUrl(IdentUrlIpv4)).Return(createHttpResponse(data.wanIp4), nil).Once()
 			}
 
 			if data.wanIp6 != "" {
-				httpClient.EXPECT().Get(IdentUrlIpv6).Return(createHttpResponse(data.wanIp6), nil).Once()
+				httpClient.EXPECT().Do(matchRequestUrl(IdentUrlIpv6)).Return(createHttpResponse(data.wanIp6), nil).Once()
 			}
 
-			actualRefreshUrl, err := client.BuildRefreshUrl(&data.domain)
+			actualRefreshUrl, err := client.BuildRefreshUrl(context.Background(), &data.domain)
 
 			assert.NoError(t, err)
 			assert.Equal(t, data.expectedUrl, actualRefreshUrl)
@@ -129,22 +411,184 @@ func TestClient_BuildRefreshUrl(t *testing.T) {
 	}
 }
 
+func TestClient_BuildRefreshRequest(t *testing.T) {
+	for _, test := range refreshTable() {
+		t.Run(test.name, func(t *testing.T) {
+			expectedRequest := createHttpRequest(test.domain)
+
+			client := Client{}
+
+			actualRequest, err := client.BuildRefreshRequest(context.Background(), &test.domain, test.domain.RefreshUrl)
+
+			assert.NoError(t, err)
+			assert.Equal(t, expectedRequest.Method, actualRequest.Method)
+			assert.Equal(t, expectedRequest.URL, actualRequest.URL)
+			assert.Equal(t, expectedRequest.Header, actualRequest.Header)
+		})
+	}
+}
+
+func TestClient_BuildRefreshUrlWithEmbeddedTemplates(t *testing.T) {
+	config.Dirs = []string{}
+	cfg, _ := config.NewConfig("test")
+
+	tests := []struct {
+		template string
+		expected map[string]string
+	}{
+		{template: "ddnss", expected: map[string]string{"": "https://ddnss.de/upd.php?key=secret&host=yddns.drieschel.org&ip=125.148.255.41&ip6=2001:db8::1"}},
+		{template: "desec", expected: map[string]string{"": "https://update.dedyn.io/?hostname=yddns.drieschel.org&myipv4=125.148.255.41&myipv6=2001:db8::1"}},
+		{template: "duckdns", expected: map[string]string{"": "https://www.duckdns.org/update?domains=yddns.drieschel.org&token=secret&ip=125.148.255.41&ipv6=2001:db8::1"}},
+		{template: "dyndns2", expected: map[string]string{"": "https://members.dyndns.org/nic/update?hostname=yddns.drieschel.org&myip=125.148.255.41,2001:db8::1&wildcard=NOCHG&mx=NOCHG&backmx=NOCHG"}},
+		{template: "dynu", expected: map[string]string{"": "https://api.dynu.com/nic/update?hostname=yddns.drieschel.org&myip=125.148.255.41&myipv6=2001:db8::1"}},
+		{template: "dynv6", expected: map[string]string{"": "https://dynv6.com/api/update?hostname=yddns.drieschel.org&token=secret&ipv4=125.148.255.41&ipv6=2001:db8::1"}},
+		{template: "freedns", expected: map[string]string{"ip4": "https://sync.afraid.org/u/secret/?ip=125.148.255.41", "ip6": "https://v6.sync.afraid.org/u/secret/?ip=2001:db8::1"}},
+		{template: "he", expected: map[string]string{"ip4": "https://dyn.dns.he.net/nic/update?hostname=yddns.drieschel.org&password=secret&myip=125.148.255.41", "ip6": "https://dyn.dns.he.net/nic/update?hostname=yddns.drieschel.org&password=secret&myip=2001:db8::1"}},
+		{template: "inwx", expected: map[string]string{"": "https://dyndns.inwx.com/nic/update?myip=125.148.255.41&myipv6=2001:db8::1"}},
+		{template: "ionos", expected: map[string]string{"ip4": "https://ipv4.api.hosting.ionos.com/dns/v1/dyndns?q=secret", "ip6": "https://ipv6.api.hosting.ionos.com/dns/v1/dyndns?q=secret"}},
+		{template: "namecheap", expected: map[string]string{"": "https://dynamicdns.park-your-domain.com/update?host=john&domain=yddns.drieschel.org&password=secret&ip=125.148.255.41"}},
+		{template: "noip", expected: map[string]string{"": "https://dynupdate.no-ip.com/nic/update?hostname=yddns.drieschel.org&myip=125.148.255.41&myipv6=2001:db8::1"}},
+		{template: "ovh", expected: map[string]string{"": "https://www.ovh.com/nic/update?system=dyndns&hostname=yddns.drieschel.org&myip=125.148.255.41"}},
+		{template: "selfhost", expected: map[string]string{"ip4": "https://carol.selfhost.de/nic/update?hostname=yddns.drieschel.org&myip=125.148.255.41", "ip6": "https://carol.selfhost.de/nic/update?hostname=yddns.drieschel.org&myip=2001:db8::1"}},
+		{template: "spdyn", expected: map[string]string{"ip4": "https://update.spdyn.de/nic/update?hostname=yddns.drieschel.org&myip=125.148.255.41", "ip6": "https://update.spdyn.de/nic/update?hostname=yddns.drieschel.org&myip=2001:db8::1"}},
+		{template: "strato", expected: map[string]string{"": "https://dyndns.strato.com/nic/update?hostname=yddns.drieschel.org&myip=125.148.255.41,2001:db8::1"}},
+	}
+
+	assert.Len(t, tests, len(cfg.Templates), "every embedded template must be covered")
+
+	for _, test := range tests {
+		t.Run(test.template, func(t *testing.T) {
+			domain := config.Domain{AuthUser: "john", AuthPassword: "secret", DomainName: "yddns.drieschel.org", Ip4Address: "125.148.255.41", Ip6Address: "2001:db8::1", Template: config.Template{RefreshUrl: config.RefreshUrlTemplatePrefix + test.template}}
+
+			err := cfg.PrepareDomain(&domain)
+			assert.NoError(t, err)
+			assert.Empty(t, cfg.ValidateDomain(&domain))
+
+			domains := []*config.Domain{&domain}
+			if domain.HasFamilyRefreshUrls() {
+				domains = domain.SplitByFamily()
+			}
+
+			client := Client{}
+			actual := map[string]string{}
+			for _, d := range domains {
+				family := ""
+				if domain.HasFamilyRefreshUrls() {
+					family = d.Families[0]
+				}
+
+				actual[family], err = client.BuildRefreshUrl(context.Background(), d)
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestClient_BuildRefreshUrlWithCombinedTemplateAndSingleFamily(t *testing.T) {
+	config.Dirs = []string{}
+	cfg, _ := config.NewConfig("test")
+
+	tests := []struct {
+		name     string
+		domain   config.Domain
+		expected map[string]string
+	}{
+		{
+			name:     "dyndns2 ip4 only",
+			domain:   config.Domain{Families: []string{"ip4"}, Template: config.Template{RefreshUrl: ":dyndns2"}},
+			expected: map[string]string{"": "https://members.dyndns.org/nic/update?hostname=yddns.drieschel.org&myip=125.148.255.41&wildcard=NOCHG&mx=NOCHG&backmx=NOCHG"},
+		},
+		{
+			name:     "strato ip6 only",
+			domain:   config.Domain{Families: []string{"ip6"}, Template: config.Template{RefreshUrl: ":strato"}},
+			expected: map[string]string{"": "https://dyndns.strato.com/nic/update?hostname=yddns.drieschel.org&myip=2001:db8::1"},
+		},
+		{
+			name:     "strato with separate ip4 url falls back to refresh url for ip6",
+			domain:   config.Domain{Template: config.Template{RefreshUrl: ":strato", RefreshUrlIp4: "https://ip4.strato.example/update?myip=<ip4>"}},
+			expected: map[string]string{"ip4": "https://ip4.strato.example/update?myip=125.148.255.41", "ip6": "https://dyndns.strato.com/nic/update?hostname=yddns.drieschel.org&myip=2001:db8::1"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			domain := test.domain
+			domain.AuthUser = "john"
+			domain.AuthPassword = "secret"
+			domain.DomainName = "yddns.drieschel.org"
+			domain.Ip4Address = "125.148.255.41"
+			domain.Ip6Address = "2001:db8::1"
+
+			err := cfg.PrepareDomain(&domain)
+			assert.NoError(t, err)
+
+			domains := []*config.Domain{&domain}
+			if domain.HasFamilyRefreshUrls() {
+				domains = domain.SplitByFamily()
+			}
+
+			client := Client{}
+			actual := map[string]string{}
+			for _, d := range domains {
+				family := ""
+				if domain.HasFamilyRefreshUrls() {
+					family = d.Families[0]
+				}
+
+				actual[family], err = client.BuildRefreshUrl(context.Background(), d)
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestClient_BuildStaticRefreshUrl(t *testing.T) {
+	tests := []struct {
+		name     string
+		domain   config.Domain
+		expected string
+	}{
+		{name: "Dynamic addresses", expected: "https://dyn.drieschel.org/update?user=john&hostname=yddns.drieschel.org&myip=<ip4>,<ip6>"},
+		{name: "Static ip4 address", domain: config.Domain{Ip4Address: "125.148.255.41"}, expected: "https://dyn.drieschel.org/update?user=john&hostname=yddns.drieschel.org&myip=125.148.255.41,<ip6>"},
+		{name: "Static addresses", domain: config.Domain{Ip4Address: "125.148.255.41", Ip6Address: "2001:db8::1"}, expected: "https://dyn.drieschel.org/update?user=john&hostname=yddns.drieschel.org&myip=125.148.255.41,2001:db8::1"},
+		{name: "Excluded ip6 family", domain: config.Domain{Families: []string{"ip4"}, Ip6Address: "2001:db8::1"}, expected: "https://dyn.drieschel.org/update?user=john&hostname=yddns.drieschel.org&myip=<ip4>"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			domain := test.domain
+			domain.AuthUser = "john"
+			domain.DomainName = "yddns.drieschel.org"
+			domain.Template = config.Template{Host: "dyn.drieschel.org", RefreshUrl: "<protocol>://<host>/update?user=<username>&hostname=<domain>&myip=<ip4>,<ip6>"}
+
+			client := Client{}
+
+			assert.Equal(t, test.expected, client.BuildStaticRefreshUrl(&domain))
+		})
+	}
+}
+
 func TestClient_BuildReplacements(t *testing.T) {
 	for _, data := range refreshUrlTable() {
 		t.Run(data.name, func(t *testing.T) {
 			cacheMock := cache.NewMockCache(t)
+			historyMock := history.NewMockHistory(t)
 			httpClient := NewMockHttpClient(t)
-			client := NewClient(cacheMock, httpClient)
+			client := NewClient(cacheMock, historyMock, resolver.NewMockResolver(t), httpClient)
 
 			if data.wanIp4 != "" {
-				httpClient.EXPECT().Get(IdentUrlIpv4).Return(createHttpResponse(data.wanIp4), nil).Once()
+				httpClient.EXPECT().Do(matchRequestUrl(IdentUrlIpv4)).Return(createHttpResponse(data.wanIp4), nil).Once()
 			}
 
 			if data.wanIp6 != "" {
-				httpClient.EXPECT().Get(IdentUrlIpv6).Return(createHttpResponse(data.wanIp6), nil).Once()
+				httpClient.EXPECT().Do(matchRequestUrl(IdentUrlIpv6)).Return(createHttpResponse(data.wanIp6), nil).Once()
 			}
 
-			replacements, err := client.BuildReplacements(&data.domain)
+			replacements, err := client.BuildReplacements(context.Background(), &data.domain)
 
 			assert.NoError(t, err)
 			assert.Equal(t, data.expectedReplacements, replacements)
@@ -208,6 +652,22 @@ func refreshUrlTable() []struct {
 			wanIp4:               "",
 			wanIp6:               "",
 		},
+		{
+			name:                 "Excluded IPv6 family no WAN IPv6 request",
+			domain:               config.Domain{Families: []string{"ip4"}, Template: config.Template{RefreshUrl: "https://fancy-dyn.dns?myip=<ip4>,<ip6>"}},
+			expectedReplacements: &map[string]string{"<username>": "", "<password>": "", "<domain>": "", "<host>": "", "<protocol>": "https", "<ip4>": "125.148.255.41", "<ip6>": ""},
+			expectedUrl:          "https://fancy-dyn.dns?myip=125.148.255.41",
+			wanIp4:               "125.148.255.41",
+			wanIp6:               "",
+		},
+		{
+			name:                 "Excluded IPv4 family no leading list separator",
+			domain:               config.Domain{Families: []string{"ip6"}, Template: config.Template{RefreshUrl: "https://fancy-dyn.dns?myip=<ip4>,<ip6>"}},
+			expectedReplacements: &map[string]string{"<username>": "", "<password>": "", "<domain>": "", "<host>": "", "<protocol>": "https", "<ip4>": "", "<ip6>": "2001:db8::1"},
+			expectedUrl:          "https://fancy-dyn.dns?myip=2001:db8::1",
+			wanIp4:               "",
+			wanIp6:               "2001:db8::1",
+		},
 		{
 			name:                 "IPv6 host id + WAN IPv6 request = WAN IPv6 prefix + host id",
 			domain:               config.Domain{Ip4Address: "192.124.234.52", Ip6HostId: "a7cc:409a:e841:ea15", Template: config.Template{RefreshUrl: "https://fancy-dyn.dns?e=<ip4>&f=<ip6>"}},
@@ -216,6 +676,140 @@ func refreshUrlTable() []struct {
 			wanIp4:               "",
 			wanIp6:               "d710:6c3b:b3c3:9f6b:a7cc:409a:e841:ea15",
 		},
+		{
+			name:                 "IPv6 compressed host id + compressed WAN IPv6 request",
+			domain:               config.Domain{Ip6HostId: "::1234", Template: config.Template{RefreshUrl: "https://fancy-dyn.dns?f=<ip6>"}},
+			expectedReplacements: &map[string]string{"<username>": "", "<password>": "", "<domain>": "", "<host>": "", "<protocol>": "https", "<ip6>": "2001:db8::1234"},
+			expectedUrl:          "https://fancy-dyn.dns?f=2001:db8::1234",
+			wanIp4:               "",
+			wanIp6:               "2001:db8::1\n",
+		},
+		{
+			name:                 "IPv6 mac EUI-64 host id + WAN IPv6 request",
+			domain:               config.Domain{Ip6Mac: "00:11:22:33:44:55", Template: config.Template{RefreshUrl: "https://fancy-dyn.dns?f=<ip6>"}},
+			expectedReplacements: &map[string]string{"<username>": "", "<password>": "", "<domain>": "", "<host>": "", "<protocol>": "https", "<ip6>": "2001:db8:0:1:211:22ff:fe33:4455"},
+			expectedUrl:          "https://fancy-dyn.dns?f=2001:db8:0:1:211:22ff:fe33:4455",
+			wanIp4:               "",
+			wanIp6:               "2001:db8:0:1::1",
+		},
+		{
+			name:                 "IPv6 static prefix + host id + prefix length no WAN request",
+			domain:               config.Domain{Ip6Prefix: "2001:db8:aa:bb00::", Ip6PrefixLength: 56, Ip6HostId: "0:0:0:12::1", Template: config.Template{RefreshUrl: "https://fancy-dyn.dns?f=<ip6>"}},
+			expectedReplacements: &map[string]string{"<username>": "", "<password>": "", "<domain>": "", "<host>": "", "<protocol>": "https", "<ip6>": "2001:db8:aa:bb12::1"},
+			expectedUrl:          "https://fancy-dyn.dns?f=2001:db8:aa:bb12::1",
+			wanIp4:               "",
+			wanIp6:               "",
+		},
+	}
+}
+
+func TestClient_DetermineAddressWithNeighborLookup(t *testing.T) {
+	domain := config.Domain{Ip6Mac: "00:11:22:33:44:55", Ip6NeighborLookup: true}
+
+	neighborTableMock := netaddr.NewMockNeighborTable(t)
+	neighborTableMock.EXPECT().Neighbors().Return([]netaddr.Neighbor{
+		{Address: netip.MustParseAddr("fe80::1"), Mac: "00:11:22:33:44:55", State: "REACHABLE"},
+		{Address: netip.MustParseAddr("2001:db8:0:9:a7cc:409a:e841:ea15"), Mac: "00:11:22:33:44:55", State: "STALE"},
+	}, nil).Once()
+
+	httpClientMock := NewMockHttpClient(t)
+	httpClientMock.EXPECT().Do(matchRequestUrl(IdentUrlIpv6)).Return(createHttpResponse("2001:db8:0:1::1"), nil).Once()
+
+	client := Client{ipSource: NewIdentIpSource(httpClientMock), neighborTable: neighborTableMock}
+
+	address, err := client.DetermineAddress(context.Background(), &domain, config.KeyIp6)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "2001:db8:0:1:a7cc:409a:e841:ea15", address)
+}
+
+func TestClient_DetermineAddressWithNeighborLookupNotFound(t *testing.T) {
+	domain := config.Domain{Ip6Mac: "00:11:22:33:44:55", Ip6NeighborLookup: true}
+
+	neighborTableMock := netaddr.NewMockNeighborTable(t)
+	neighborTableMock.EXPECT().Neighbors().Return([]netaddr.Neighbor{}, nil).Once()
+
+	client := Client{neighborTable: neighborTableMock}
+
+	_, err := client.DetermineAddress(context.Background(), &domain, config.KeyIp6)
+
+	assert.EqualError(t, err, "no global ipv6 neighbor address found for mac address \"00:11:22:33:44:55\"")
+}
+
+func TestClient_DetermineAddressWithInterface(t *testing.T) {
+	interfaceAddresses := []netaddr.InterfaceAddress{
+		{Address: netip.MustParseAddr("2001:db8:0:1:1234:5678:abcd:ef01"), Device: "eth0", Flags: netaddr.FlagTemporary, PrefixLength: 64},
+		{Address: netip.MustParseAddr("2001:db8:0:1::1"), Device: "eth0", Flags: netaddr.FlagPermanent, PrefixLength: 64},
+		{Address: netip.MustParseAddr("2001:db8:0:1:211:22ff:fe33:4455"), Device: "eth0", PrefixLength: 64},
+	}
+
+	for _, test := range []struct {
+		name     string
+		domain   config.Domain
+		expected string
+	}{
+		{name: "Stable address", domain: config.Domain{Ip6Interface: "eth0"}, expected: "2001:db8:0:1::1"},
+		{name: "Preferred EUI-64 address", domain: config.Domain{Ip6Interface: "eth0", Ip6InterfacePrefer: netaddr.Ip6PreferEui64}, expected: "2001:db8:0:1:211:22ff:fe33:4455"},
+		{name: "Interface prefix with host id", domain: config.Domain{Ip6Interface: "eth0", Ip6HostId: "::abcd"}, expected: "2001:db8:0:1::abcd"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			interfaceAddressTableMock := netaddr.NewMockInterfaceAddressTable(t)
+			interfaceAddressTableMock.EXPECT().InterfaceAddresses().Return(interfaceAddresses, nil).Once()
+
+			client := Client{interfaceAddressTable: interfaceAddressTableMock}
+
+			address, err := client.DetermineAddress(context.Background(), &test.domain, config.KeyIp6)
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, address)
+		})
+	}
+}
+
+func TestClient_DetermineAddressWithAddressPolicy(t *testing.T) {
+	for _, test := range []struct {
+		name            string
+		addressPolicy   string
+		ip4Address      string
+		expected        string
+		expectedErr     string
+		expectedWarning string
+	}{
+		{name: "Warned by default", expected: "100.64.12.34", expectedWarning: "ip4 address of domain \"yddns.drieschel.org\": address 100.64.12.34 is in the carrier-grade nat range 100.64.0.0/10"},
+		{name: "Rejected", addressPolicy: config.AddressPolicyReject, expectedErr: "ip4 address of domain \"yddns.drieschel.org\" rejected by address_policy \"reject\": address 100.64.12.34 is in the carrier-grade nat range 100.64.0.0/10"},
+		{name: "Static address rejected", addressPolicy: config.AddressPolicyReject, ip4Address: "10.0.0.2", expectedErr: "ip4 address of domain \"yddns.drieschel.org\" rejected by address_policy \"reject\": address 10.0.0.2 is a private address"},
+		{name: "Allowed", addressPolicy: config.AddressPolicyAllow, expected: "100.64.12.34"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			domain := config.Domain{DomainName: "yddns.drieschel.org", AddressPolicy: test.addressPolicy, Ip4Address: test.ip4Address}
+
+			httpClientMock := NewMockHttpClient(t)
+			if test.ip4Address == "" {
+				httpClientMock.EXPECT().Do(matchRequestUrl(IdentUrlIpv4)).Return(createHttpResponse("100.64.12.34"), nil).Once()
+			}
+
+			client := Client{ipSource: NewIdentIpSource(httpClientMock)}
+
+			warning := ""
+			client.SetWarningHandler(func(domain *config.Domain, err error) {
+				warning = err.Error()
+			})
+
+			address, err := client.DetermineAddress(context.Background(), &domain, config.KeyIp4)
+
+			if test.expectedErr != "" {
+				assert.ErrorContains(t, err, test.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, test.expected, address)
+			if test.expectedWarning != "" {
+				assert.Contains(t, warning, test.expectedWarning)
+			} else {
+				assert.Empty(t, warning)
+			}
+		})
 	}
 }
 
@@ -226,16 +820,16 @@ func TestClient_DetermineWanIp4(t *testing.T) {
 	expectedIp := uuid.New().String()
 
 	httpClientMock := NewMockHttpClient(t)
-	httpClientMock.EXPECT().Get(expectedUrl).Return(createHttpResponse(expectedIp), nil).Once()
-	c.httpClient = httpClientMock
+	httpClientMock.EXPECT().Do(matchRequestUrl(expectedUrl)).Return(createHttpResponse(expectedIp), nil).Once()
+	c.ipSource = NewIdentIpSource(httpClientMock)
 
-	actualIp, err := c.DetermineWanIp4()
+	actualIp, err := c.DetermineWanIp4(context.Background())
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedIp, actualIp)
 
 	//determine ip cached
-	actualIp, err = c.DetermineWanIp4()
+	actualIp, err = c.DetermineWanIp4(context.Background())
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedIp, actualIp)
@@ -248,16 +842,16 @@ func TestClient_DetermineWanIp6(t *testing.T) {
 	expectedIp := uuid.New().String()
 
 	httpClientMock := NewMockHttpClient(t)
-	httpClientMock.EXPECT().Get(expectedUrl).Return(createHttpResponse(expectedIp), nil).Once()
-	c.httpClient = httpClientMock
+	httpClientMock.EXPECT().Do(matchRequestUrl(expectedUrl)).Return(createHttpResponse(expectedIp), nil).Once()
+	c.ipSource = NewIdentIpSource(httpClientMock)
 
-	actualIp, err := c.DetermineWanIp6()
+	actualIp, err := c.DetermineWanIp6(context.Background())
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedIp, actualIp)
 
 	//determine ip cached
-	actualIp, err = c.DetermineWanIp6()
+	actualIp, err = c.DetermineWanIp6(context.Background())
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedIp, actualIp)
@@ -292,3 +886,9 @@ func createHttpResponse(responseBody string) *http.Response {
 		Body:       io.NopCloser(bytes.NewBufferString(responseBody)),
 	}
 }
+
+func matchRequestUrl(url string) any {
+	return mock.MatchedBy(func(request *http.Request) bool {
+		return request.URL.String() == url
+	})
+}
@@ -5,6 +5,7 @@
 package client
 
 import (
+	"context"
 	"net/http"
 
 	mock "github.com/stretchr/testify/mock"
@@ -99,64 +100,95 @@ func (_c *MockHttpClient_Do_Call) RunAndReturn(run func(req *http.Request) (*htt
 	return _c
 }
 
-// Get provides a mock function for the type MockHttpClient
-func (_mock *MockHttpClient) Get(url string) (*http.Response, error) {
-	ret := _mock.Called(url)
+// NewMockIpSource creates a new instance of MockIpSource. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockIpSource(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockIpSource {
+	mock := &MockIpSource{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockIpSource is an autogenerated mock type for the IpSource type
+type MockIpSource struct {
+	mock.Mock
+}
+
+type MockIpSource_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockIpSource) EXPECT() *MockIpSource_Expecter {
+	return &MockIpSource_Expecter{mock: &_m.Mock}
+}
+
+// Address provides a mock function for the type MockIpSource
+func (_mock *MockIpSource) Address(ctx context.Context, family string) (string, error) {
+	ret := _mock.Called(ctx, family)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Get")
+		panic("no return value specified for Address")
 	}
 
-	var r0 *http.Response
+	var r0 string
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(string) (*http.Response, error)); ok {
-		return returnFunc(url)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+		return returnFunc(ctx, family)
 	}
-	if returnFunc, ok := ret.Get(0).(func(string) *http.Response); ok {
-		r0 = returnFunc(url)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = returnFunc(ctx, family)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*http.Response)
-		}
+		r0 = ret.Get(0).(string)
 	}
-	if returnFunc, ok := ret.Get(1).(func(string) error); ok {
-		r1 = returnFunc(url)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, family)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockHttpClient_Get_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Get'
-type MockHttpClient_Get_Call struct {
+// MockIpSource_Address_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Address'
+type MockIpSource_Address_Call struct {
 	*mock.Call
 }
 
-// Get is a helper method to define mock.On call
-//   - url string
-func (_e *MockHttpClient_Expecter) Get(url interface{}) *MockHttpClient_Get_Call {
-	return &MockHttpClient_Get_Call{Call: _e.mock.On("Get", url)}
+// Address is a helper method to define mock.On call
+//   - ctx context.Context
+//   - family string
+func (_e *MockIpSource_Expecter) Address(ctx interface{}, family interface{}) *MockIpSource_Address_Call {
+	return &MockIpSource_Address_Call{Call: _e.mock.On("Address", ctx, family)}
 }
 
-func (_c *MockHttpClient_Get_Call) Run(run func(url string)) *MockHttpClient_Get_Call {
+func (_c *MockIpSource_Address_Call) Run(run func(ctx context.Context, family string)) *MockIpSource_Address_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 string
+		var arg0 context.Context
 		if args[0] != nil {
-			arg0 = args[0].(string)
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
 		}
 		run(
 			arg0,
+			arg1,
 		)
 	})
 	return _c
 }
 
-func (_c *MockHttpClient_Get_Call) Return(resp *http.Response, err error) *MockHttpClient_Get_Call {
-	_c.Call.Return(resp, err)
+func (_c *MockIpSource_Address_Call) Return(s string, err error) *MockIpSource_Address_Call {
+	_c.Call.Return(s, err)
 	return _c
 }
 
-func (_c *MockHttpClient_Get_Call) RunAndReturn(run func(url string) (*http.Response, error)) *MockHttpClient_Get_Call {
+func (_c *MockIpSource_Address_Call) RunAndReturn(run func(ctx context.Context, family string) (string, error)) *MockIpSource_Address_Call {
 	_c.Call.Return(run)
 	return _c
 }
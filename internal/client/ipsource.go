@@ -0,0 +1,54 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/drieschel/yddns/internal/config"
+)
+
+type IpSource interface {
+	Address(ctx context.Context, family string) (string, error)
+}
+
+type IdentIpSource struct {
+	httpClient HttpClient
+}
+
+func NewIdentIpSource(httpClient HttpClient) *IdentIpSource {
+	return &IdentIpSource{httpClient: httpClient}
+}
+
+func (s *IdentIpSource) Address(ctx context.Context, family string) (string, error) {
+	var identUrl string
+	switch family {
+	case config.KeyIp4:
+		identUrl = IdentUrlIpv4
+	case config.KeyIp6:
+		identUrl = IdentUrlIpv6
+	default:
+		return "", fmt.Errorf("family \"%s\" is not supported", family)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, identUrl, nil)
+	if err != nil {
+		return "", err
+	}
+
+	response, err := s.httpClient.Do(request)
+	if err != nil {
+		return "", err
+	}
+
+	defer response.Body.Close()
+
+	ip, err := io.ReadAll(response.Body)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(ip)), nil
+}
@@ -0,0 +1,54 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestIdentIpSource_Address(t *testing.T) {
+	tests := []struct {
+		family      string
+		identUrl    string
+		response    string
+		expected    string
+		expectedErr string
+	}{
+		{family: "ip4", identUrl: IdentUrlIpv4, response: "125.148.255.41\n", expected: "125.148.255.41"},
+		{family: "ip6", identUrl: IdentUrlIpv6, response: " 2001:db8::1 ", expected: "2001:db8::1"},
+		{family: "ip5", expectedErr: "family \"ip5\" is not supported"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.family, func(t *testing.T) {
+			httpClientMock := NewMockHttpClient(t)
+			if test.identUrl != "" {
+				httpClientMock.EXPECT().Do(matchRequestUrl(test.identUrl)).Return(createHttpResponse(test.response), nil).Once()
+			}
+
+			actual, err := NewIdentIpSource(httpClientMock).Address(context.Background(), test.family)
+
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestClient_DetermineWanIp4WithIpSource(t *testing.T) {
+	ipSourceMock := NewMockIpSource(t)
+	ipSourceMock.EXPECT().Address(mock.Anything, "ip4").Return("", errors.New("no route")).Once()
+
+	c := Client{ipSource: ipSourceMock}
+
+	_, err := c.DetermineWanIp4(context.Background())
+
+	assert.EqualError(t, err, "no route")
+}
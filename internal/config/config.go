@@ -1,13 +1,21 @@
 package config
 
 import (
-	"encoding/json"
+	"bytes"
+	"embed"
+	"errors"
 	"fmt"
-	"log"
+	iofs "io/fs"
+	"maps"
+	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/drieschel/yddns/internal/cache"
+	"github.com/drieschel/yddns/internal/history"
+	"github.com/drieschel/yddns/internal/netaddr"
+	"github.com/go-viper/mapstructure/v2"
 	"github.com/spf13/afero"
 	"github.com/spf13/viper"
 )
@@ -15,6 +23,10 @@ import (
 const (
 	AppName = "yddns"
 
+	AddressPolicyAllow  = "allow"
+	AddressPolicyReject = "reject"
+	AddressPolicyWarn   = "warn"
+
 	AuthMethodBasic  = "basic"
 	AuthMethodBearer = "bearer"
 
@@ -24,85 +36,183 @@ const (
 	RequestMethodGet  = "GET"
 	RequestMethodPost = "POST"
 
+	DefaultAddressPolicy   = AddressPolicyWarn
 	DefaultAppVersion      = "dev"
 	DefaultAuthMethod      = AuthMethodBasic
 	DefaultRequestMethod   = RequestMethodGet
 	DefaultProtocol        = ProtocolHttps
 	DefaultRefreshInterval = 60
+	DefaultVerifyTimeout   = 60
 
 	KeyDomains                    = "domains"
+	KeyTemplates                  = "templates"
 	KeyRefreshInterval            = "refresh_interval"
 	KeyCacheCreatedExpirySeconds  = "cache_max_ttl"
 	KeyCacheModifiedExpirySeconds = "cache_ttl"
-
-	KeyAuthMethod    = "auth_method"
-	KeyDomainName    = "domain"
-	KeyHost          = "host"
-	KeyIp4Address    = "ip4_address"
-	KeyIp6Address    = "ip6_address"
-	KeyIp6HostId     = "ip6_host_id"
-	KeyPassword      = "password"
-	KeyProtocol      = "protocol"
-	KeyRefreshUrl    = "refresh_url"
-	KeyRequestMethod = "request_method"
-	KeyUserAgent     = "user_agent"
-	KeyUsername      = "username"
+	KeyHistoryMaxEntries          = "history_max_entries"
+	KeyHistoryMaxAgeSeconds       = "history_max_age"
+
+	KeyAddressPolicy      = "address_policy"
+	KeyAuthMethod         = "auth_method"
+	KeyDomainName         = "domain"
+	KeyExtends            = "extends"
+	KeyFamilies           = "families"
+	KeyHost               = "host"
+	KeyIp4Address         = "ip4_address"
+	KeyIp6Address         = "ip6_address"
+	KeyIp6HostId          = "ip6_host_id"
+	KeyIp6Interface       = "ip6_interface"
+	KeyIp6InterfacePrefer = "ip6_interface_prefer"
+	KeyIp6InterfaceSuffix = "ip6_interface_suffix"
+	KeyIp6Mac             = "ip6_mac"
+	KeyIp6NeighborLookup  = "ip6_neighbor_lookup"
+	KeyIp6Prefix          = "ip6_prefix"
+	KeyIp6PrefixLength    = "ip6_prefix_length"
+	KeyPassword           = "password"
+	KeyProtocol           = "protocol"
+	KeyRefreshUrl         = "refresh_url"
+	KeyRefreshUrlIp4      = "refresh_url_ip4"
+	KeyRefreshUrlIp6      = "refresh_url_ip6"
+	KeyRequestMethod      = "request_method"
+	KeyResolver           = "resolver"
+	KeySkipIfResolved     = "skip_if_resolved"
+	KeyUserAgent          = "user_agent"
+	KeyUsername           = "username"
+	KeyVerify             = "verify"
+	KeyVerifyTimeout      = "verify_timeout"
 
 	KeyIp4 = "ip4"
 	KeyIp6 = "ip6"
 
+	EnvConfigDir    = "YDDNS_CONFIG_DIR"
+	EnvXdgStateHome = "XDG_STATE_HOME"
+
 	DirNameCache     = "cache"
+	DirNameConfD     = "conf.d"
+	DirNameHistory   = "history"
 	DirNameTemplates = "templates"
 
 	RefreshUrlTemplatePrefix = ":"
+
+	EmbeddedTemplatesSource = "embedded:templates"
 )
 
+//go:embed templates/*.json
+var embeddedTemplates embed.FS
+
 var (
-	Dirs                    = []string{fmt.Sprintf("/etc/%s", AppName), determineAppDir()}
-	FilePath                = ""
-	SupportedAuthMethods    = []string{AuthMethodBasic, AuthMethodBearer}
-	SupportedProtocols      = []string{ProtocolHttp, ProtocolHttps}
-	SupportedRequestMethods = []string{RequestMethodGet, RequestMethodPost}
+	Dirs                     = determineDirs()
+	FilePath                 = ""
+	SupportedAddressPolicies = []string{AddressPolicyReject, AddressPolicyWarn, AddressPolicyAllow}
+	SupportedAuthMethods     = []string{AuthMethodBasic, AuthMethodBearer}
+	SupportedFamilies        = []string{KeyIp4, KeyIp6}
+	SupportedFileExtensions  = []string{"toml", "json", "yaml", "yml"}
+	SupportedProtocols       = []string{ProtocolHttp, ProtocolHttps}
+	SupportedRequestMethods  = []string{RequestMethodGet, RequestMethodPost}
 )
 
 type Config struct {
-	AppDir                     string
 	AppVersion                 string
-	CacheCreatedExpirySeconds  int                  `mapstructure:"cache_max_ttl"`
-	CacheModifiedExpirySeconds int                  `mapstructure:"cache_ttl"`
-	Domains                    []*Domain            `mapstructure:"domains"`
-	Templates                  map[string]*Template `mapstructure:"templates"`
-	RefreshInterval            int                  `mapstructure:"refresh_interval"`
+	CacheCreatedExpirySeconds  int                   `mapstructure:"cache_max_ttl"`
+	CacheDir                   string                `mapstructure:"-"`
+	CacheModifiedExpirySeconds int                   `mapstructure:"cache_ttl"`
+	ConfFiles                  []string              `mapstructure:"-"`
+	Domains                    []*Domain             `mapstructure:"domains"`
+	File                       string                `mapstructure:"-"`
+	HistoryMaxAgeSeconds       int                   `mapstructure:"history_max_age"`
+	HistoryMaxEntries          int                   `mapstructure:"history_max_entries"`
+	ShadowedTemplates          []*TemplateDefinition `mapstructure:"-"`
+	StateDir                   string                `mapstructure:"-"`
+	Templates                  map[string]*Template  `mapstructure:"templates"`
+	TemplateSources            map[string]string     `mapstructure:"-"`
+	RefreshInterval            int                   `mapstructure:"refresh_interval"`
+	unknownKeys                map[string][]string
+}
+
+type confFileConfig struct {
+	Domains   []*Domain            `mapstructure:"domains"`
+	Templates map[string]*Template `mapstructure:"templates"`
 }
 
-func NewConfig(appVersion string) *Config {
+func NewConfig(appVersion string) (*Config, error) {
+	cacheDir, err := determineCacheDir()
+	if err != nil {
+		return nil, err
+	}
+
+	stateDir, err := determineStateDir()
+	if err != nil {
+		return nil, err
+	}
+
 	c := &Config{
-		AppDir:                     determineAppDir(),
 		AppVersion:                 appVersion,
 		CacheCreatedExpirySeconds:  cache.CreatedExpirySecondsDefault,
+		CacheDir:                   cacheDir,
 		CacheModifiedExpirySeconds: cache.ModifiedExpirySecondsDefault,
 		Domains:                    []*Domain{},
+		HistoryMaxAgeSeconds:       history.MaxAgeSecondsDefault,
+		HistoryMaxEntries:          history.MaxEntriesDefault,
 		Templates:                  map[string]*Template{},
+		TemplateSources:            map[string]string{},
 		RefreshInterval:            DefaultRefreshInterval,
+		StateDir:                   stateDir,
 	}
 
-	err := readFileTemplates(c)
+	err = readFileTemplates(c)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
-	return c
+	return c, nil
 }
 
-func NewFileConfig(appVersion string) *Config {
-	c := NewConfig(appVersion)
+func NewFileConfig(appVersion string) (*Config, error) {
+	c, err := NewConfig(appVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.ReadFile(FilePath)
+	if err != nil {
+		return nil, err
+	}
 
-	err := readFileConfig(c)
+	return c, nil
+}
+
+func NewOptionalFileConfig(appVersion string) (*Config, error) {
+	c, err := NewConfig(appVersion)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
+	}
+
+	var notFoundErr *NotFoundError
+	err = c.ReadFile(FilePath)
+	if err != nil && (!errors.As(err, &notFoundErr) || notFoundErr.File != "") {
+		return nil, err
 	}
 
-	return c
+	return c, nil
+}
+
+func (c *Config) ReadFile(filePath string) error {
+	return readFileConfig(c, filePath)
+}
+
+func (c *Config) AddDomains(domains ...*Domain) error {
+	c.Domains = append(c.Domains, domains...)
+
+	return checkDuplicateDomains(c.Domains)
+}
+
+func (c *Config) AddTemplate(name string, template *Template, source string) {
+	if shadowed, exist := c.Templates[name]; exist {
+		c.ShadowedTemplates = append(c.ShadowedTemplates, &TemplateDefinition{Name: name, Source: c.TemplateSources[name], Template: shadowed})
+	}
+
+	c.Templates[name] = template
+	c.TemplateSources[name] = source
 }
 
 func (c *Config) GetAppVersion() string {
@@ -118,14 +228,30 @@ func (c *Config) GetDefaultUserAgent() string {
 }
 
 func (c *Config) GetTemplate(name string) (*Template, error) {
-	if template, ok := c.Templates[name]; ok {
-		return template, nil
+	return c.resolveTemplate(name, []string{})
+}
+
+func (c *Config) ListTemplates() []*TemplateDefinition {
+	var definitions []*TemplateDefinition
+	for _, templateName := range slices.Sorted(maps.Keys(c.Templates)) {
+		source := c.TemplateSources[templateName]
+		definitions = append(definitions, &TemplateDefinition{Name: templateName, Source: source, Template: c.Templates[templateName]})
+
+		for _, shadowed := range c.ShadowedTemplates {
+			if shadowed.Name == templateName {
+				definitions = append(definitions, &TemplateDefinition{Name: templateName, Source: shadowed.Source, OverriddenBy: source, Template: shadowed.Template})
+			}
+		}
 	}
 
-	return &Template{}, fmt.Errorf("template \"%s\" not found", name)
+	return definitions
 }
 
 func (c *Config) PrepareDomain(d *Domain) error {
+	if d.Extends != "" {
+		return fmt.Errorf("domain \"%s\" cannot extend a template, use refresh_url \"%s%s\" instead", d.DomainName, RefreshUrlTemplatePrefix, d.Extends)
+	}
+
 	if d.RequiresTemplate() {
 		templateName, _ := d.GetTemplateName()
 		template, err := c.GetTemplate(templateName)
@@ -136,6 +262,48 @@ func (c *Config) PrepareDomain(d *Domain) error {
 		d.MergeTemplate(template)
 	}
 
+	for _, family := range d.Families {
+		if !slices.Contains(SupportedFamilies, family) {
+			return fmt.Errorf("family \"%s\" of domain \"%s\" is not supported", family, d.DomainName)
+		}
+	}
+
+	if d.AddressPolicy != "" && !slices.Contains(SupportedAddressPolicies, d.AddressPolicy) {
+		return fmt.Errorf("address policy \"%s\" of domain \"%s\" is not supported", d.AddressPolicy, d.DomainName)
+	}
+
+	if d.Ip6HostId != "" && d.Ip6Mac != "" {
+		return fmt.Errorf("ip6 host id and ip6 mac of domain \"%s\" cannot be combined", d.DomainName)
+	}
+
+	if d.Ip6NeighborLookup && d.Ip6Mac == "" {
+		return fmt.Errorf("ip6 neighbor lookup of domain \"%s\" requires an ip6 mac", d.DomainName)
+	}
+
+	if d.Ip6Prefix != "" && d.Ip6HostId == "" && d.Ip6Mac == "" {
+		return fmt.Errorf("ip6 prefix of domain \"%s\" requires an ip6 host id or ip6 mac", d.DomainName)
+	}
+
+	if d.Ip6InterfacePrefer != "" && !slices.Contains(netaddr.SupportedIp6Preferences, d.Ip6InterfacePrefer) {
+		return fmt.Errorf("ip6 interface preference \"%s\" of domain \"%s\" is not supported", d.Ip6InterfacePrefer, d.DomainName)
+	}
+
+	if d.Ip6InterfacePrefer != "" && d.Ip6Interface == "" {
+		return fmt.Errorf("ip6 interface preference of domain \"%s\" requires an ip6 interface", d.DomainName)
+	}
+
+	if (d.Ip6InterfacePrefer == netaddr.Ip6PreferSuffix) != (d.Ip6InterfaceSuffix != "") {
+		return fmt.Errorf("ip6 interface suffix of domain \"%s\" must be set together with ip6 interface preference \"%s\"", d.DomainName, netaddr.Ip6PreferSuffix)
+	}
+
+	if d.Ip6PrefixLength < 0 || d.Ip6PrefixLength > 128 {
+		return fmt.Errorf("ip6 prefix length %d of domain \"%s\" is invalid", d.Ip6PrefixLength, d.DomainName)
+	}
+
+	if d.HasFamilyRefreshUrls() && len(d.SplitByFamily()) == 0 {
+		return fmt.Errorf("domain \"%s\" has no refresh url for families [%s]", d.DomainName, strings.Join(d.GetFamilies(), ", "))
+	}
+
 	d.InitDefaultValues(c.GetAppVersion())
 
 	return nil
@@ -143,10 +311,11 @@ func (c *Config) PrepareDomain(d *Domain) error {
 
 func (c *Config) PrepareAndGetDomains() ([]*Domain, error) {
 	var domains []*Domain
-	for _, d := range c.Domains {
+	indexes := createDomainIndexes(c.Domains)
+	for i, d := range c.Domains {
 		err := c.PrepareDomain(d)
 		if err != nil {
-			return domains, err
+			return domains, &ValidationError{File: d.Source, Domain: d.DomainName, Index: indexes[i], Err: err}
 		}
 
 		domains = append(domains, d)
@@ -156,92 +325,397 @@ func (c *Config) PrepareAndGetDomains() ([]*Domain, error) {
 }
 
 func (c *Config) CreateFileCache() cache.Cache {
-	cacheDir := filepath.Join(c.AppDir, DirNameCache)
+	return cache.NewFileCache(c.CacheDir, c.CacheCreatedExpirySeconds, c.CacheModifiedExpirySeconds)
+}
+
+func (c *Config) CreateFileHistory() *history.FileHistory {
+	historyDir := filepath.Join(c.StateDir, DirNameHistory)
+
+	return history.NewFileHistory(historyDir, c.HistoryMaxEntries, c.HistoryMaxAgeSeconds)
+}
+
+func (c *Config) resolveTemplate(name string, chain []string) (*Template, error) {
+	template, ok := c.Templates[name]
+	if !ok {
+		return &Template{}, &TemplateNotFoundError{Name: name}
+	}
+
+	if template.Extends == "" {
+		return template, nil
+	}
+
+	chain = append(chain, name)
+	if slices.Contains(chain, template.Extends) {
+		return &Template{}, fmt.Errorf("template \"%s\" has cyclic extends (%s -> %s)", chain[0], strings.Join(chain, " -> "), template.Extends)
+	}
+
+	if _, exist := c.Templates[template.Extends]; !exist {
+		return &Template{}, &TemplateNotFoundError{Name: template.Extends, ExtendedBy: name}
+	}
+
+	parent, err := c.resolveTemplate(template.Extends, chain)
+	if err != nil {
+		return &Template{}, err
+	}
+
+	resolved := *template
+	resolved.Merge(parent)
 
-	return cache.NewFileCache(cacheDir, c.CacheCreatedExpirySeconds, c.CacheModifiedExpirySeconds)
+	return &resolved, nil
 }
 
 func readFileTemplates(c *Config) error {
 	fs := afero.NewOsFs()
 
-	for i := len(Dirs) - 1; i >= 0; i-- {
-		dir := Dirs[i]
+	for _, dir := range Dirs {
 		templatesDir := filepath.Join(dir, DirNameTemplates)
 		if exists, _ := afero.DirExists(fs, templatesDir); !exists {
 			continue
 		}
 
 		jailedFs := afero.NewBasePathFs(fs, templatesDir)
-		io := afero.NewIOFS(jailedFs)
-		templateFiles, err := io.Glob("*.json")
+		err := readTemplates(c, afero.NewIOFS(jailedFs), templatesDir)
 		if err != nil {
 			return err
 		}
+	}
 
-		for _, file := range templateFiles {
-			templateName, _ := strings.CutSuffix(file, ".json")
+	embeddedFs, _ := iofs.Sub(embeddedTemplates, DirNameTemplates)
 
-			if _, exist := c.Templates[templateName]; exist {
-				continue
-			}
+	return readTemplates(c, embeddedFs, EmbeddedTemplatesSource)
+}
 
-			var data []byte
-			data, err = io.ReadFile(file)
-			if err != nil {
-				return err
+func readTemplates(c *Config, fsys iofs.FS, sourceDir string) error {
+	var templateFiles []string
+	for _, ext := range SupportedFileExtensions {
+		files, err := iofs.Glob(fsys, "*."+ext)
+		if err != nil {
+			return err
+		}
+
+		templateFiles = append(templateFiles, files...)
+	}
+
+	slices.Sort(templateFiles)
+
+	dirSources := map[string]string{}
+	for _, file := range templateFiles {
+		source := filepath.Join(sourceDir, file)
+		templates, err := readTemplateFile(fsys, file)
+		if err != nil {
+			return &ParseError{File: source, Err: err}
+		}
+
+		for _, templateName := range slices.Sorted(maps.Keys(templates)) {
+			if dirSource, exist := dirSources[templateName]; exist {
+				return fmt.Errorf("template \"%s\" is defined in %s and %s", templateName, dirSource, source)
 			}
 
-			template := &Template{}
-			err = json.Unmarshal(data, &template)
-			if err != nil {
-				return err
+			dirSources[templateName] = source
+			template := templates[templateName]
+
+			if _, exist := c.Templates[templateName]; exist {
+				c.ShadowedTemplates = append(c.ShadowedTemplates, &TemplateDefinition{Name: templateName, Source: source, Template: template})
+				continue
 			}
 
 			c.Templates[templateName] = template
+			c.TemplateSources[templateName] = source
 		}
 	}
 
 	return nil
 }
 
-func readFileConfig(c *Config) error {
-	viper.SupportedExts = []string{"toml", "json", "yaml", "yml"}
-	viper.SetConfigFile(FilePath)
+func readTemplateFile(fsys iofs.FS, file string) (map[string]*Template, error) {
+	data, err := iofs.ReadFile(fsys, file)
+	if err != nil {
+		return nil, err
+	}
+
+	ext := filepath.Ext(file)
+	v := viper.New()
+	v.SetConfigType(ext[1:])
+
+	err = v.ReadConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	templates := map[string]*Template{}
+	if v.IsSet(KeyTemplates) {
+		return templates, v.UnmarshalKey(KeyTemplates, &templates)
+	}
+
+	template := &Template{}
+	templates[strings.TrimSuffix(file, ext)] = template
+
+	return templates, v.Unmarshal(template)
+}
+
+func readFileConfig(c *Config, filePath string) error {
+	v := viper.New()
+	viper.SupportedExts = SupportedFileExtensions
+	v.SetConfigFile(filePath)
 
-	if FilePath == "" {
-		viper.SetConfigName("config")
+	if filePath == "" {
+		v.SetConfigName("config")
 		for _, path := range Dirs {
-			viper.AddConfigPath(path)
+			v.AddConfigPath(path)
 		}
 	}
 
-	err := viper.ReadInConfig()
+	confFiles, err := findConfFiles(getConfDirs(filePath))
 	if err != nil {
 		return err
 	}
 
-	err = viper.Unmarshal(c)
+	envDomains, unknownEnvKeys, err := readEnvDomains()
 	if err != nil {
 		return err
 	}
 
+	err = v.ReadInConfig()
+	c.File = v.ConfigFileUsed()
+	if errors.As(err, &viper.ConfigFileNotFoundError{}) {
+		if len(confFiles) == 0 && len(envDomains) == 0 {
+			return &NotFoundError{Dirs: Dirs}
+		}
+
+		c.File = ""
+	} else if errors.Is(err, iofs.ErrNotExist) {
+		return &NotFoundError{File: filePath}
+	} else if err != nil {
+		return &ParseError{File: c.File, Err: err}
+	}
+
+	bindEnvConfig(v)
+	fileTemplates := maps.Clone(c.Templates)
+	c.unknownKeys = map[string][]string{}
+	if len(unknownEnvKeys) > 0 {
+		c.unknownKeys[EnvSourcePrefix+EnvPrefix] = unknownEnvKeys
+	}
+
+	err = unmarshalConfigFile(c, v, c.File, c)
+	if err != nil {
+		return err
+	}
+
+	for _, d := range c.Domains {
+		d.Source = c.File
+	}
+
+	for templateName := range v.GetStringMap(KeyTemplates) {
+		if template, exist := fileTemplates[templateName]; exist {
+			c.ShadowedTemplates = append(c.ShadowedTemplates, &TemplateDefinition{Name: templateName, Source: c.TemplateSources[templateName], Template: template})
+		}
+
+		c.TemplateSources[templateName] = c.File
+	}
+
+	for _, file := range confFiles {
+		err = readConfFile(c, file, fileTemplates)
+		if err != nil {
+			return err
+		}
+
+		c.ConfFiles = append(c.ConfFiles, file)
+	}
+
+	c.Domains = append(c.Domains, envDomains...)
+
+	return checkDuplicateDomains(c.Domains)
+}
+
+func readConfFile(c *Config, file string, fileTemplates map[string]*Template) error {
+	v := viper.New()
+	v.SetConfigFile(file)
+
+	err := v.ReadInConfig()
+	if err != nil {
+		return &ParseError{File: file, Err: err}
+	}
+
+	confConfig := &confFileConfig{}
+	err = unmarshalConfigFile(c, v, file, confConfig)
+	if err != nil {
+		return err
+	}
+
+	for _, d := range confConfig.Domains {
+		d.Source = file
+		c.Domains = append(c.Domains, d)
+	}
+
+	for _, templateName := range slices.Sorted(maps.Keys(confConfig.Templates)) {
+		if source, exist := c.TemplateSources[templateName]; exist && (source == c.File || slices.Contains(c.ConfFiles, source)) {
+			return &ValidationError{File: file, Index: -1, Err: fmt.Errorf("template \"%s\" is already defined in %s", templateName, source)}
+		}
+
+		if template, exist := fileTemplates[templateName]; exist {
+			c.ShadowedTemplates = append(c.ShadowedTemplates, &TemplateDefinition{Name: templateName, Source: c.TemplateSources[templateName], Template: template})
+		}
+
+		c.Templates[templateName] = confConfig.Templates[templateName]
+		c.TemplateSources[templateName] = file
+	}
+
 	return nil
 }
 
+func unmarshalConfigFile(c *Config, v *viper.Viper, file string, target any) error {
+	metadata := &mapstructure.Metadata{}
+	err := v.Unmarshal(target, func(dc *mapstructure.DecoderConfig) { dc.Metadata = metadata })
+	if err != nil {
+		return &ValidationError{File: file, Index: -1, Err: err}
+	}
+
+	if len(metadata.Unused) > 0 {
+		c.unknownKeys[file] = metadata.Unused
+	}
+
+	return nil
+}
+
+func findConfFiles(confDirs []string) ([]string, error) {
+	var confFiles []string
+	for _, confDir := range confDirs {
+		var dirFiles []string
+		for _, ext := range SupportedFileExtensions {
+			files, err := filepath.Glob(filepath.Join(confDir, "*."+ext))
+			if err != nil {
+				return confFiles, err
+			}
+
+			dirFiles = append(dirFiles, files...)
+		}
+
+		slices.Sort(dirFiles)
+		confFiles = append(confFiles, dirFiles...)
+	}
+
+	return confFiles, nil
+}
+
+func checkDuplicateDomains(domains []*Domain) error {
+	indexes := createDomainIndexes(domains)
+	for i, d := range domains {
+		if d.DomainName == "" {
+			continue
+		}
+
+		for j, other := range domains[:i] {
+			if d.DomainName != other.DomainName {
+				continue
+			}
+
+			for _, family := range d.GetFamilies() {
+				if other.HasFamily(family) {
+					return &ValidationError{File: d.Source, Domain: d.DomainName, Index: indexes[i], Err: fmt.Errorf("%s is already defined in %s: domains[%d]", family, other.Source, indexes[j])}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func createDomainIndexes(domains []*Domain) []int {
+	var indexes []int
+	fileIndexes := map[string]int{}
+	for _, d := range domains {
+		indexes = append(indexes, fileIndexes[d.Source])
+		fileIndexes[d.Source]++
+	}
+
+	return indexes
+}
+
+func GetConfDirs() []string {
+	return getConfDirs(FilePath)
+}
+
+func getConfDirs(filePath string) []string {
+	if filePath != "" {
+		return []string{filepath.Join(filepath.Dir(filePath), DirNameConfD)}
+	}
+
+	var confDirs []string
+	for _, dir := range Dirs {
+		confDirs = append(confDirs, filepath.Join(dir, DirNameConfD))
+	}
+
+	return confDirs
+}
+
 func CreateDefaultUserAgent(version string) string {
 	return fmt.Sprintf("%s/%s", AppName, version)
 }
 
-func determineAppDir() string {
-	execDir, err := filepath.Abs("./")
+func determineAppDir() (string, error) {
+	execFile, err := os.Executable()
+	if err == nil {
+		execFile, err = filepath.EvalSymlinks(execFile)
+	}
+
 	if err != nil {
-		log.Fatal(err)
+		return "", fmt.Errorf("unable to determine app dir: %w", err)
 	}
 
+	execDir := filepath.Dir(execFile)
 	parentDir, dirName := filepath.Split(execDir)
 	if dirName == "bin" {
-		return parentDir
+		return filepath.Clean(parentDir), nil
+	}
+
+	return execDir, nil
+}
+
+func determineDirs() []string {
+	var dirs []string
+	if dir := os.Getenv(EnvConfigDir); dir != "" {
+		dirs = append(dirs, dir)
+	}
+
+	if dir, err := os.UserConfigDir(); err == nil {
+		dirs = append(dirs, filepath.Join(dir, AppName))
+	}
+
+	if dir, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, filepath.Join(dir, "."+AppName))
+	}
+
+	dirs = append(dirs, fmt.Sprintf("/etc/%s", AppName))
+	if dir, err := determineAppDir(); err == nil {
+		dirs = append(dirs, dir)
+	}
+
+	return slices.Compact(dirs)
+}
+
+func determineCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err == nil {
+		return filepath.Join(dir, AppName), nil
+	}
+
+	appDir, err := determineAppDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(appDir, DirNameCache), nil
+}
+
+func determineStateDir() (string, error) {
+	if dir := os.Getenv(EnvXdgStateHome); filepath.IsAbs(dir) {
+		return filepath.Join(dir, AppName), nil
+	}
+
+	dir, err := os.UserHomeDir()
+	if err == nil {
+		return filepath.Join(dir, ".local", "state", AppName), nil
 	}
 
-	return execDir
+	return determineAppDir()
 }
@@ -2,10 +2,13 @@ package config
 
 import (
 	"fmt"
+	iofs "io/fs"
+	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/google/uuid"
+	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -13,7 +16,9 @@ func TestConfig_NewFileConfig(t *testing.T) {
 	thisDir, _ := filepath.Abs("./")
 	Dirs = []string{fmt.Sprintf("%s/testdata", thisDir)}
 
-	cfg := NewFileConfig("42")
+	cfg, err := NewFileConfig("42")
+
+	assert.NoError(t, err)
 
 	assert.Equal(t, 42, cfg.RefreshInterval)
 	assert.Equal(t, 1, len(cfg.Domains))
@@ -26,13 +31,27 @@ func TestConfig_NewFileConfig(t *testing.T) {
 	assert.Equal(t, "a5", cfg.Domains[0].Ip4Address)
 	assert.Equal(t, "a6", cfg.Domains[0].Ip6Address)
 	assert.Equal(t, "a7", cfg.Domains[0].Ip6HostId)
+	assert.Equal(t, "a15", cfg.Domains[0].Ip6Prefix)
+	assert.Equal(t, 56, cfg.Domains[0].Ip6PrefixLength)
+	assert.Equal(t, "a16", cfg.Domains[0].Ip6Mac)
+	assert.Equal(t, "a17", cfg.Domains[0].Ip6Interface)
+	assert.Equal(t, "a18", cfg.Domains[0].Ip6InterfacePrefer)
+	assert.Equal(t, "a19", cfg.Domains[0].Ip6InterfaceSuffix)
+	assert.Equal(t, "a20", cfg.Domains[0].AddressPolicy)
+	assert.Equal(t, true, cfg.Domains[0].Ip6NeighborLookup)
 	assert.Equal(t, "a8", cfg.Domains[0].AuthPassword)
 	assert.Equal(t, "a9", cfg.Domains[0].RefreshUrl)
 	assert.Equal(t, "a10", cfg.Domains[0].AuthUser)
 	assert.Equal(t, "a11", cfg.Domains[0].UserAgent)
 	assert.Equal(t, "a12", cfg.Domains[0].RequestMethod)
+	assert.Equal(t, "a13", cfg.Domains[0].Resolver)
+	assert.Equal(t, []string{"ip4"}, cfg.Domains[0].Families)
+	assert.Equal(t, true, cfg.Domains[0].SkipIfResolved)
+	assert.Equal(t, true, cfg.Domains[0].Verify)
+	assert.Equal(t, 14, cfg.Domains[0].VerifyTimeout)
 
-	assert.Equal(t, 3, len(cfg.Templates))
+	embeddedFiles, _ := iofs.Glob(embeddedTemplates, "templates/*.json")
+	assert.Equal(t, 4+len(embeddedFiles)-1, len(cfg.Templates))
 	assert.Contains(t, cfg.Templates, "yddns")
 	assert.Contains(t, cfg.Templates, "stat-dns")
 	assert.Contains(t, cfg.Templates, "provider")
@@ -45,6 +64,8 @@ func TestConfig_NewFileConfig(t *testing.T) {
 	assert.Equal(t, "y4", cfg.Templates["yddns"].RefreshUrl)
 	assert.Equal(t, "y5", cfg.Templates["yddns"].RequestMethod)
 	assert.Equal(t, "y6", cfg.Templates["yddns"].UserAgent)
+	assert.Equal(t, "y7", cfg.Templates["yddns"].RefreshUrlIp4)
+	assert.Equal(t, "y8", cfg.Templates["yddns"].RefreshUrlIp6)
 
 	//Dedicated file templates are getting properly unmarshalled as well
 	assert.Equal(t, "p1", cfg.Templates["provider"].AuthMethod)
@@ -53,6 +74,290 @@ func TestConfig_NewFileConfig(t *testing.T) {
 	assert.Equal(t, "p4", cfg.Templates["provider"].RefreshUrl)
 	assert.Equal(t, "p5", cfg.Templates["provider"].RequestMethod)
 	assert.Equal(t, "p6", cfg.Templates["provider"].UserAgent)
+	assert.Equal(t, "p7", cfg.Templates["provider"].RefreshUrlIp4)
+	assert.Equal(t, "p8", cfg.Templates["provider"].RefreshUrlIp6)
+
+	//Config file and template sources are getting tracked
+	assert.Equal(t, filepath.Join(thisDir, "testdata", "config.json"), cfg.File)
+	assert.Equal(t, cfg.File, cfg.TemplateSources["yddns"])
+	assert.Equal(t, cfg.File, cfg.TemplateSources["stat-dns"])
+	assert.Equal(t, filepath.Join(thisDir, "testdata", "templates", "provider.json"), cfg.TemplateSources["provider"])
+
+	//Embedded templates have the lowest priority
+	assert.Equal(t, "embedded:templates/duckdns.json", cfg.TemplateSources["duckdns"])
+	assert.Equal(t, "d2", cfg.Templates["dyndns2"].Host)
+	assert.Equal(t, filepath.Join(thisDir, "testdata", "templates", "dyndns2.json"), cfg.TemplateSources["dyndns2"])
+
+	//Shadowed templates are listed with the overriding source
+	var definitions []*TemplateDefinition
+	for _, definition := range cfg.ListTemplates() {
+		if definition.Name == "yddns" || definition.Name == "dyndns2" {
+			definitions = append(definitions, definition)
+		}
+	}
+
+	assert.Len(t, definitions, 4)
+	assert.Equal(t, "embedded:templates/dyndns2.json", definitions[1].Source)
+	assert.Equal(t, cfg.TemplateSources["dyndns2"], definitions[1].OverriddenBy)
+	assert.Equal(t, &TemplateDefinition{Name: "yddns", Source: cfg.File, Template: cfg.Templates["yddns"]}, definitions[2])
+	assert.Equal(t, filepath.Join(thisDir, "testdata", "templates", "yddns.json"), definitions[3].Source)
+	assert.Equal(t, cfg.File, definitions[3].OverriddenBy)
+	assert.Equal(t, "x2", definitions[3].Template.Host)
+}
+
+func TestConfig_NewFileConfigWithConfD(t *testing.T) {
+	dir := t.TempDir()
+	Dirs = []string{dir}
+	confDir := filepath.Join(dir, DirNameConfD)
+	_ = os.MkdirAll(confDir, 0755)
+	_ = os.WriteFile(filepath.Join(dir, "config.toml"), []byte("refresh_interval = 42\n[[domains]]\ndomain = \"main.example.org\"\n[templates.main]\nhost = \"main.example.org\""), 0600)
+	_ = os.WriteFile(filepath.Join(confDir, "20-second.yaml"), []byte("domains:\n  - domain: second.example.org\n"), 0600)
+	_ = os.WriteFile(filepath.Join(confDir, "10-first.toml"), []byte("[[domains]]\ndomain = \"first.example.org\"\n[templates.first]\nhost = \"first.example.org\""), 0600)
+	_ = os.WriteFile(filepath.Join(confDir, "README.md"), []byte("ignored"), 0600)
+
+	cfg, err := NewFileConfig("42")
+
+	assert.NoError(t, err)
+
+	assert.Equal(t, 42, cfg.RefreshInterval)
+	assert.Equal(t, filepath.Join(dir, "config.toml"), cfg.File)
+	assert.Equal(t, []string{filepath.Join(confDir, "10-first.toml"), filepath.Join(confDir, "20-second.yaml")}, cfg.ConfFiles)
+	assert.Len(t, cfg.Domains, 3)
+	assert.Equal(t, "main.example.org", cfg.Domains[0].DomainName)
+	assert.Equal(t, cfg.File, cfg.Domains[0].Source)
+	assert.Equal(t, "first.example.org", cfg.Domains[1].DomainName)
+	assert.Equal(t, cfg.ConfFiles[0], cfg.Domains[1].Source)
+	assert.Equal(t, "second.example.org", cfg.Domains[2].DomainName)
+	assert.Equal(t, cfg.ConfFiles[1], cfg.Domains[2].Source)
+	assert.Equal(t, "first.example.org", cfg.Templates["first"].Host)
+	assert.Equal(t, cfg.ConfFiles[0], cfg.TemplateSources["first"])
+	assert.Equal(t, cfg.File, cfg.TemplateSources["main"])
+}
+
+func TestConfig_NewFileConfigWithConfDOnly(t *testing.T) {
+	dir := t.TempDir()
+	Dirs = []string{dir}
+	confDir := filepath.Join(dir, DirNameConfD)
+	_ = os.MkdirAll(confDir, 0755)
+	_ = os.WriteFile(filepath.Join(confDir, "domain.json"), []byte("{\"domains\": [{\"domain\": \"only.example.org\"}]}"), 0600)
+
+	cfg, err := NewFileConfig("42")
+
+	assert.NoError(t, err)
+
+	assert.Empty(t, cfg.File)
+	assert.Equal(t, DefaultRefreshInterval, cfg.RefreshInterval)
+	assert.Len(t, cfg.Domains, 1)
+	assert.Equal(t, filepath.Join(confDir, "domain.json"), cfg.Domains[0].Source)
+}
+
+func TestReadFileConfigWithDuplicates(t *testing.T) {
+	dir := t.TempDir()
+	Dirs = []string{dir}
+	confDir := filepath.Join(dir, DirNameConfD)
+	_ = os.MkdirAll(confDir, 0755)
+	_ = os.WriteFile(filepath.Join(dir, "config.toml"), []byte("[[domains]]\ndomain = \"dupe.example.org\"\nfamilies = [\"ip4\"]\n[templates.dupe]\nhost = \"dupe.example.org\""), 0600)
+	_ = os.WriteFile(filepath.Join(confDir, "ip6.toml"), []byte("[[domains]]\ndomain = \"dupe.example.org\"\nfamilies = [\"ip6\"]"), 0600)
+
+	cfg, _ := NewConfig("42")
+	err := readFileConfig(cfg, "")
+	assert.NoError(t, err)
+
+	_ = os.WriteFile(filepath.Join(confDir, "both.toml"), []byte("[[domains]]\ndomain = \"other.example.org\"\n[[domains]]\ndomain = \"dupe.example.org\""), 0600)
+
+	cfg, _ = NewConfig("42")
+	err = readFileConfig(cfg, "")
+	assert.EqualError(t, err, fmt.Sprintf("%s: domains[1] \"dupe.example.org\": ip4 is already defined in %s: domains[0]", filepath.Join(confDir, "both.toml"), filepath.Join(dir, "config.toml")))
+
+	_ = os.Remove(filepath.Join(confDir, "both.toml"))
+	_ = os.WriteFile(filepath.Join(confDir, "template.toml"), []byte("[templates.dupe]\nhost = \"other.example.org\""), 0600)
+
+	cfg, _ = NewConfig("42")
+	err = readFileConfig(cfg, "")
+	assert.EqualError(t, err, fmt.Sprintf("%s: template \"dupe\" is already defined in %s", filepath.Join(confDir, "template.toml"), filepath.Join(dir, "config.toml")))
+}
+
+func TestReadFileConfigWithUnnamedDomains(t *testing.T) {
+	dir := t.TempDir()
+	Dirs = []string{dir}
+	_ = os.WriteFile(filepath.Join(dir, "config.toml"), []byte("[[domains]]\nrefresh_url = \"https://dyn.example.org/first?ip=<ip4>\"\n[[domains]]\nrefresh_url = \"https://dyn.example.org/second?ip=<ip4>\""), 0600)
+
+	cfg, _ := NewConfig("42")
+	err := readFileConfig(cfg, "")
+
+	assert.NoError(t, err)
+	assert.Len(t, cfg.Domains, 2)
+}
+
+func TestConfig_ReadFile(t *testing.T) {
+	Dirs = []string{}
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "custom.toml")
+	_ = os.MkdirAll(filepath.Join(dir, DirNameConfD), 0755)
+	_ = os.WriteFile(filePath, []byte("refresh_interval = 120\n[[domains]]\ndomain = \"first.example.org\""), 0600)
+	_ = os.WriteFile(filepath.Join(dir, DirNameConfD, "second.toml"), []byte("[[domains]]\ndomain = \"second.example.org\""), 0600)
+
+	cfg, _ := NewConfig("42")
+	err := cfg.ReadFile(filePath)
+
+	assert.NoError(t, err)
+	assert.Equal(t, filePath, cfg.File)
+	assert.Equal(t, 120, cfg.RefreshInterval)
+	assert.Len(t, cfg.Domains, 2)
+
+	cfg, _ = NewConfig("42")
+	err = cfg.ReadFile(filepath.Join(dir, "missing.toml"))
+
+	assert.Equal(t, &NotFoundError{File: filepath.Join(dir, "missing.toml")}, err)
+}
+
+func TestConfig_AddDomains(t *testing.T) {
+	cfg := &Config{Domains: []*Domain{{DomainName: "first.example.org"}}}
+
+	assert.NoError(t, cfg.AddDomains(&Domain{DomainName: "second.example.org"}))
+	assert.Len(t, cfg.Domains, 2)
+	assert.Error(t, cfg.AddDomains(&Domain{DomainName: "first.example.org"}))
+}
+
+func TestConfig_AddTemplate(t *testing.T) {
+	original := &Template{Host: "original.example.org"}
+	cfg := &Config{Templates: map[string]*Template{"dyn": original}, TemplateSources: map[string]string{"dyn": EmbeddedTemplatesSource}}
+
+	cfg.AddTemplate("dyn", &Template{Host: "custom.example.org"}, "option")
+
+	assert.Equal(t, "custom.example.org", cfg.Templates["dyn"].Host)
+	assert.Equal(t, "option", cfg.TemplateSources["dyn"])
+	assert.Equal(t, []*TemplateDefinition{{Name: "dyn", Source: EmbeddedTemplatesSource, Template: original}}, cfg.ShadowedTemplates)
+}
+
+func TestReadTemplates(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	_ = afero.WriteFile(fs, "single.toml", []byte("host = \"single.example.org\"\nextends = \"pack-one\""), 0644)
+	_ = afero.WriteFile(fs, "pack.yaml", []byte("templates:\n  pack-one:\n    host: one.example.org\n    refresh_url: <protocol>://<host>/update\n  pack-two:\n    host: two.example.org\n"), 0644)
+	_ = afero.WriteFile(fs, "pack.yml.bak", []byte("invalid"), 0644)
+	cfg := &Config{Templates: map[string]*Template{"pack-two": {Host: "config.example.org"}}, TemplateSources: map[string]string{}}
+
+	err := readTemplates(cfg, afero.NewIOFS(fs), "/dir")
+
+	assert.NoError(t, err)
+	assert.Len(t, cfg.Templates, 3)
+	assert.Equal(t, &Template{Extends: "pack-one", Host: "single.example.org"}, cfg.Templates["single"])
+	assert.Equal(t, &Template{Host: "one.example.org", RefreshUrl: "<protocol>://<host>/update"}, cfg.Templates["pack-one"])
+	assert.Equal(t, "config.example.org", cfg.Templates["pack-two"].Host)
+	assert.Equal(t, filepath.Join("/dir", "single.toml"), cfg.TemplateSources["single"])
+	assert.Equal(t, filepath.Join("/dir", "pack.yaml"), cfg.TemplateSources["pack-one"])
+	assert.Equal(t, []*TemplateDefinition{{Name: "pack-two", Source: filepath.Join("/dir", "pack.yaml"), Template: &Template{Host: "two.example.org"}}}, cfg.ShadowedTemplates)
+}
+
+func TestReadTemplatesWithNameCollision(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	_ = afero.WriteFile(fs, "provider.json", []byte("{\"host\": \"json.example.org\"}"), 0644)
+	_ = afero.WriteFile(fs, "pack.toml", []byte("[templates.provider]\nhost = \"toml.example.org\""), 0644)
+	cfg := &Config{Templates: map[string]*Template{}, TemplateSources: map[string]string{}}
+
+	err := readTemplates(cfg, afero.NewIOFS(fs), "/dir")
+
+	assert.EqualError(t, err, fmt.Sprintf("template \"provider\" is defined in %s and %s", filepath.Join("/dir", "pack.toml"), filepath.Join("/dir", "provider.json")))
+}
+
+func TestReadTemplatesWithInvalidFile(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	_ = afero.WriteFile(fs, "broken.json", []byte("{"), 0644)
+	cfg := &Config{Templates: map[string]*Template{}, TemplateSources: map[string]string{}}
+
+	err := readTemplates(cfg, afero.NewIOFS(fs), "/dir")
+
+	var parseErr *ParseError
+	assert.ErrorAs(t, err, &parseErr)
+	assert.Equal(t, filepath.Join("/dir", "broken.json"), parseErr.File)
+	assert.ErrorContains(t, err, fmt.Sprintf("%s is invalid", filepath.Join("/dir", "broken.json")))
+}
+
+func TestConfig_NewFileConfigErrors(t *testing.T) {
+	dir := t.TempDir()
+	Dirs = []string{dir}
+
+	var notFoundErr *NotFoundError
+	_, err := NewFileConfig("42")
+	assert.ErrorAs(t, err, &notFoundErr)
+	assert.EqualError(t, err, fmt.Sprintf("no config file found in %s", dir))
+
+	cfg, err := NewOptionalFileConfig("42")
+	assert.NoError(t, err)
+	assert.Empty(t, cfg.File)
+
+	FilePath = filepath.Join(dir, "404.toml")
+	defer func() { FilePath = "" }()
+
+	_, err = NewOptionalFileConfig("42")
+	assert.ErrorAs(t, err, &notFoundErr)
+	assert.EqualError(t, err, fmt.Sprintf("config file %s not found", FilePath))
+
+	FilePath = filepath.Join(dir, "config.toml")
+	_ = os.WriteFile(FilePath, []byte("[[domains]"), 0600)
+
+	var parseErr *ParseError
+	_, err = NewFileConfig("42")
+	assert.ErrorAs(t, err, &parseErr)
+	assert.Equal(t, FilePath, parseErr.File)
+}
+
+func TestDetermineDirs(t *testing.T) {
+	t.Setenv(EnvConfigDir, "/custom/yddns")
+	t.Setenv("XDG_CONFIG_HOME", "/xdg/config")
+	t.Setenv("HOME", "/home/jane")
+
+	dirs := determineDirs()
+
+	appDir, _ := determineAppDir()
+	assert.Equal(t, []string{"/custom/yddns", "/xdg/config/yddns", "/home/jane/.yddns", "/etc/yddns", appDir}, dirs)
+}
+
+func TestDetermineCacheAndStateDir(t *testing.T) {
+	t.Setenv("HOME", "/home/jane")
+	t.Setenv("XDG_CACHE_HOME", "")
+	t.Setenv(EnvXdgStateHome, "")
+
+	cacheDir, _ := determineCacheDir()
+	stateDir, _ := determineStateDir()
+	assert.Equal(t, "/home/jane/.cache/yddns", cacheDir)
+	assert.Equal(t, "/home/jane/.local/state/yddns", stateDir)
+
+	t.Setenv("XDG_CACHE_HOME", "/xdg/cache")
+	t.Setenv(EnvXdgStateHome, "/xdg/state")
+
+	cacheDir, _ = determineCacheDir()
+	stateDir, _ = determineStateDir()
+	assert.Equal(t, "/xdg/cache/yddns", cacheDir)
+	assert.Equal(t, "/xdg/state/yddns", stateDir)
+}
+
+func TestConfig_NewConfigTemplatePrecedence(t *testing.T) {
+	first, second := t.TempDir(), t.TempDir()
+	Dirs = []string{first, second}
+	for _, dir := range Dirs {
+		_ = os.MkdirAll(filepath.Join(dir, DirNameTemplates), 0755)
+		_ = os.WriteFile(filepath.Join(dir, DirNameTemplates, "provider.json"), []byte(fmt.Sprintf("{\"host\": \"%s\"}", dir)), 0644)
+	}
+
+	cfg, err := NewConfig("42")
+
+	assert.NoError(t, err)
+
+	assert.Equal(t, first, cfg.Templates["provider"].Host)
+	assert.Equal(t, filepath.Join(first, DirNameTemplates, "provider.json"), cfg.TemplateSources["provider"])
+	assert.Equal(t, filepath.Join(second, DirNameTemplates, "provider.json"), cfg.ShadowedTemplates[0].Source)
+}
+
+func TestGetConfDirs(t *testing.T) {
+	Dirs = []string{"/first", "/second"}
+
+	assert.Equal(t, []string{"/first/conf.d", "/second/conf.d"}, GetConfDirs())
+
+	FilePath = "/custom/config.toml"
+	defer func() { FilePath = "" }()
+
+	assert.Equal(t, []string{"/custom/conf.d"}, GetConfDirs())
 }
 
 func TestConfig_GetAppVersion(t *testing.T) {
@@ -83,8 +388,99 @@ func TestConfig_GetTemplate(t *testing.T) {
 func TestConfig_GetTemplateNotFound(t *testing.T) {
 	cfg := &Config{Templates: map[string]*Template{}}
 
+	var notFoundErr *TemplateNotFoundError
 	_, err := cfg.GetTemplate("404")
-	assert.Errorf(t, err, "template \"%s\" not found", "404")
+	assert.ErrorAs(t, err, &notFoundErr)
+	assert.EqualError(t, err, "template \"404\" not found")
+}
+
+func TestConfig_GetTemplateWithExtends(t *testing.T) {
+	templates := map[string]*Template{
+		"base":    {AuthMethod: "basic", Host: "base.example.org", Protocol: "https", RefreshUrl: "<protocol>://<host>/update"},
+		"variant": {Extends: "base", Host: "variant.example.org"},
+		"nested":  {Extends: "variant", Protocol: "http"},
+	}
+	cfg := &Config{Templates: templates}
+
+	actualTemplate, err := cfg.GetTemplate("nested")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &Template{AuthMethod: "basic", Extends: "variant", Host: "variant.example.org", Protocol: "http", RefreshUrl: "<protocol>://<host>/update"}, actualTemplate)
+	assert.Equal(t, &Template{Extends: "variant", Protocol: "http"}, templates["nested"])
+}
+
+func TestConfig_GetTemplateWithInvalidExtends(t *testing.T) {
+	templates := map[string]*Template{
+		"self":    {Extends: "self"},
+		"one":     {Extends: "two"},
+		"two":     {Extends: "three"},
+		"three":   {Extends: "one"},
+		"orphan":  {Extends: "404"},
+		"adopter": {Extends: "orphan"},
+	}
+	cfg := &Config{Templates: templates}
+
+	_, err := cfg.GetTemplate("self")
+	assert.EqualError(t, err, "template \"self\" has cyclic extends (self -> self)")
+
+	_, err = cfg.GetTemplate("two")
+	assert.EqualError(t, err, "template \"two\" has cyclic extends (two -> three -> one -> two)")
+
+	var notFoundErr *TemplateNotFoundError
+	_, err = cfg.GetTemplate("adopter")
+	assert.ErrorAs(t, err, &notFoundErr)
+	assert.EqualError(t, err, "template \"orphan\" extends unknown template \"404\"")
+}
+
+func TestConfig_PrepareDomainWithExtends(t *testing.T) {
+	cfg := &Config{Templates: map[string]*Template{"base": {}}}
+
+	err := cfg.PrepareDomain(&Domain{DomainName: "dome", Template: Template{Extends: "base"}})
+	assert.EqualError(t, err, "domain \"dome\" cannot extend a template, use refresh_url \":base\" instead")
+}
+
+func TestConfig_PrepareDomainWithUnsupportedFamily(t *testing.T) {
+	cfg := &Config{Templates: map[string]*Template{}}
+
+	err := cfg.PrepareDomain(&Domain{DomainName: "dome", Families: []string{"ip5"}})
+	assert.EqualError(t, err, "family \"ip5\" of domain \"dome\" is not supported")
+}
+
+func TestConfig_PrepareDomainWithoutFamilyRefreshUrl(t *testing.T) {
+	cfg := &Config{Templates: map[string]*Template{}}
+
+	err := cfg.PrepareDomain(&Domain{DomainName: "dome", Families: []string{"ip6"}, Template: Template{RefreshUrlIp4: "four"}})
+	assert.EqualError(t, err, "domain \"dome\" has no refresh url for families [ip6]")
+}
+
+func TestConfig_PrepareDomainWithIp6PrefixWithoutHostId(t *testing.T) {
+	cfg := &Config{Templates: map[string]*Template{}}
+
+	err := cfg.PrepareDomain(&Domain{DomainName: "dome", Ip6Prefix: "2001:db8::/56"})
+	assert.EqualError(t, err, "ip6 prefix of domain \"dome\" requires an ip6 host id or ip6 mac")
+}
+
+func TestConfig_PrepareDomainWithInvalidIp6InterfacePreference(t *testing.T) {
+	cfg := &Config{Templates: map[string]*Template{}}
+
+	err := cfg.PrepareDomain(&Domain{DomainName: "dome", Ip6Interface: "eth0", Ip6InterfacePrefer: "random"})
+	assert.EqualError(t, err, "ip6 interface preference \"random\" of domain \"dome\" is not supported")
+
+	err = cfg.PrepareDomain(&Domain{DomainName: "dome", Ip6InterfacePrefer: "eui64"})
+	assert.EqualError(t, err, "ip6 interface preference of domain \"dome\" requires an ip6 interface")
+
+	err = cfg.PrepareDomain(&Domain{DomainName: "dome", Ip6Interface: "eth0", Ip6InterfacePrefer: "suffix"})
+	assert.EqualError(t, err, "ip6 interface suffix of domain \"dome\" must be set together with ip6 interface preference \"suffix\"")
+}
+
+func TestConfig_PrepareDomainWithAddressPolicy(t *testing.T) {
+	cfg := &Config{Templates: map[string]*Template{}}
+
+	err := cfg.PrepareDomain(&Domain{DomainName: "dome", AddressPolicy: "ignore"})
+	assert.EqualError(t, err, "address policy \"ignore\" of domain \"dome\" is not supported")
+
+	err = cfg.PrepareDomain(&Domain{DomainName: "dome", Ip4Address: "192.168.178.2", AddressPolicy: AddressPolicyReject})
+	assert.NoError(t, err)
 }
 
 func TestCreateDefaultUserAgent(t *testing.T) {
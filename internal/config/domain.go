@@ -1,18 +1,130 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
+	"slices"
+
+	"github.com/drieschel/yddns/internal/netaddr"
 )
 
 type Domain struct {
-	Template     `mapstructure:",squash"`
-	AuthUser     string `mapstructure:"username"`
-	AuthPassword string `mapstructure:"password"`
-	DomainName   string `mapstructure:"domain"`
-	Ip4Address   string `mapstructure:"ip4_address"`
-	Ip6Address   string `mapstructure:"ip6_address"`
-	Ip6HostId    string `mapstructure:"ip6_host_id"`
+	Template           `mapstructure:",squash"`
+	AddressPolicy      string   `mapstructure:"address_policy"`
+	AuthUser           string   `mapstructure:"username"`
+	AuthPassword       string   `mapstructure:"password"`
+	DomainName         string   `mapstructure:"domain"`
+	Families           []string `mapstructure:"families"`
+	Ip4Address         string   `mapstructure:"ip4_address"`
+	Ip6Address         string   `mapstructure:"ip6_address"`
+	Ip6HostId          string   `mapstructure:"ip6_host_id"`
+	Ip6Interface       string   `mapstructure:"ip6_interface"`
+	Ip6InterfacePrefer string   `mapstructure:"ip6_interface_prefer"`
+	Ip6InterfaceSuffix string   `mapstructure:"ip6_interface_suffix"`
+	Ip6Mac             string   `mapstructure:"ip6_mac"`
+	Ip6NeighborLookup  bool     `mapstructure:"ip6_neighbor_lookup"`
+	Ip6Prefix          string   `mapstructure:"ip6_prefix"`
+	Ip6PrefixLength    int      `mapstructure:"ip6_prefix_length"`
+	Resolver           string   `mapstructure:"resolver"`
+	SkipIfResolved     bool     `mapstructure:"skip_if_resolved"`
+	Source             string   `mapstructure:"-"`
+	Verify             bool     `mapstructure:"verify"`
+	VerifyTimeout      int      `mapstructure:"verify_timeout"`
+}
+
+func (d *Domain) ApplyAddressPolicy(family string, address string) (warning error, err error) {
+	policy := d.GetAddressPolicy()
+	if policy == AddressPolicyAllow {
+		return nil, nil
+	}
+
+	err = netaddr.CheckPublicAddress(address)
+	if err == nil {
+		return nil, nil
+	}
+
+	var classErr *netaddr.ClassError
+	if policy == AddressPolicyWarn && errors.As(err, &classErr) {
+		return fmt.Errorf("%s address of domain \"%s\": %w", family, d.DomainName, err), nil
+	}
+
+	return nil, fmt.Errorf("%s address of domain \"%s\" rejected by %s \"%s\": %w", family, d.DomainName, KeyAddressPolicy, policy, err)
+}
+
+func (d *Domain) GetAddressPolicy() string {
+	if d.AddressPolicy == "" {
+		return DefaultAddressPolicy
+	}
+
+	return d.AddressPolicy
+}
+
+func (d *Domain) GetFamilies() []string {
+	if len(d.Families) == 0 {
+		return SupportedFamilies
+	}
+
+	return d.Families
+}
+
+func (d *Domain) GetIp6PrefixLength() int {
+	if d.Ip6PrefixLength == 0 {
+		return netaddr.Ip6PrefixLengthDefault
+	}
+
+	return d.Ip6PrefixLength
+}
+
+func (d *Domain) GetRefreshUrl(family string) string {
+	if family == KeyIp4 && d.RefreshUrlIp4 != "" {
+		return d.RefreshUrlIp4
+	}
+
+	if family == KeyIp6 && d.RefreshUrlIp6 != "" {
+		return d.RefreshUrlIp6
+	}
+
+	return d.RefreshUrl
+}
+
+func (d *Domain) GetStaticAddress(family string) string {
+	switch family {
+	case KeyIp4:
+		return d.Ip4Address
+	case KeyIp6:
+		return d.Ip6Address
+	}
+
+	return ""
+}
+
+func (d *Domain) HasFamily(family string) bool {
+	return slices.Contains(d.GetFamilies(), family)
+}
+
+func (d *Domain) HasFamilyRefreshUrls() bool {
+	return d.RefreshUrlIp4 != "" || d.RefreshUrlIp6 != ""
+}
+
+func (d *Domain) SplitByFamily() []*Domain {
+	var domains []*Domain
+	for _, family := range d.GetFamilies() {
+		refreshUrl := d.GetRefreshUrl(family)
+		if refreshUrl == "" {
+			continue
+		}
+
+		familyDomain := *d
+		familyDomain.Families = []string{family}
+		familyDomain.RefreshUrl = refreshUrl
+		familyDomain.RefreshUrlIp4 = ""
+		familyDomain.RefreshUrlIp6 = ""
+
+		domains = append(domains, &familyDomain)
+	}
+
+	return domains
 }
 
 func (d *Domain) GetTemplateName() (string, error) {
@@ -39,33 +151,55 @@ func (d *Domain) InitDefaultValues(appVersion string) {
 	if d.UserAgent == "" {
 		d.UserAgent = CreateDefaultUserAgent(appVersion)
 	}
+
+	if d.VerifyTimeout == 0 {
+		d.VerifyTimeout = DefaultVerifyTimeout
+	}
 }
 
 func (d *Domain) MergeTemplate(t *Template) {
-	tType := reflect.TypeOf(*t)
-	tValue := reflect.ValueOf(*t)
-	dValue := reflect.ValueOf(&d.Template).Elem()
-
 	d.RefreshUrl = ""
-	for i := 0; i < tType.NumField(); i++ {
-		tFieldValue := tValue.Field(i)
-		dFieldValue := dValue.Field(i)
-
-		if dFieldValue.String() == "" && tFieldValue.String() != "" {
-			dFieldValue.SetString(tFieldValue.String())
-		}
-	}
+	d.Template.Merge(t)
 }
 
 func (d *Domain) RequiresTemplate() bool {
 	return len(d.RefreshUrl) > 0 && d.RefreshUrl[0:len(RefreshUrlTemplatePrefix)] == RefreshUrlTemplatePrefix
 }
 
+type TemplateDefinition struct {
+	Name         string    `json:"name"`
+	Source       string    `json:"source"`
+	OverriddenBy string    `json:"overridden_by"`
+	Template     *Template `json:"template"`
+}
+
 type Template struct {
 	AuthMethod    string `json:"auth_method" mapstructure:"auth_method"`
+	Extends       string `json:"extends" mapstructure:"extends"`
 	Host          string `json:"host" mapstructure:"host"`
 	Protocol      string `json:"protocol" mapstructure:"protocol"`
 	RefreshUrl    string `json:"refresh_url" mapstructure:"refresh_url"`
+	RefreshUrlIp4 string `json:"refresh_url_ip4" mapstructure:"refresh_url_ip4"`
+	RefreshUrlIp6 string `json:"refresh_url_ip6" mapstructure:"refresh_url_ip6"`
 	RequestMethod string `json:"request_method" mapstructure:"request_method"`
 	UserAgent     string `json:"user_agent" mapstructure:"user_agent"`
 }
+
+func (t *Template) Merge(parent *Template) {
+	tType := reflect.TypeOf(*t)
+	tValue := reflect.ValueOf(t).Elem()
+	pValue := reflect.ValueOf(*parent)
+
+	for i := 0; i < tType.NumField(); i++ {
+		if tType.Field(i).Name == "Extends" {
+			continue
+		}
+
+		tFieldValue := tValue.Field(i)
+		pFieldValue := pValue.Field(i)
+
+		if tFieldValue.String() == "" && pFieldValue.String() != "" {
+			tFieldValue.SetString(pFieldValue.String())
+		}
+	}
+}
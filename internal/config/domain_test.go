@@ -48,13 +48,13 @@ func initDefaultValuesTables() []struct {
 	}{
 		{
 			name:           "No default values set",
-			givenDomain:    Domain{Template: Template{AuthMethod: "foo", Protocol: "bar", RequestMethod: "yes", UserAgent: "yddns/1.0.3"}},
-			expectedDomain: Domain{Template: Template{AuthMethod: "foo", Protocol: "bar", RequestMethod: "yes", UserAgent: "yddns/1.0.3"}},
+			givenDomain:    Domain{Template: Template{AuthMethod: "foo", Protocol: "bar", RequestMethod: "yes", UserAgent: "yddns/1.0.3"}, VerifyTimeout: 5},
+			expectedDomain: Domain{Template: Template{AuthMethod: "foo", Protocol: "bar", RequestMethod: "yes", UserAgent: "yddns/1.0.3"}, VerifyTimeout: 5},
 		},
 		{
 			name:           "All default values set",
 			givenDomain:    Domain{Template: Template{AuthMethod: "", Protocol: "", RequestMethod: "", UserAgent: ""}},
-			expectedDomain: Domain{Template: Template{AuthMethod: "basic", Protocol: "https", RequestMethod: "GET", UserAgent: "yddns/42.0"}},
+			expectedDomain: Domain{Template: Template{AuthMethod: "basic", Protocol: "https", RequestMethod: "GET", UserAgent: "yddns/42.0"}, VerifyTimeout: 60},
 		},
 	}
 }
@@ -70,6 +70,13 @@ func TestDomain_MergeTemplate(t *testing.T) {
 	}
 }
 
+func TestTemplate_Merge(t *testing.T) {
+	template := &Template{Extends: "base", Host: "variant.example.org"}
+	template.Merge(&Template{Extends: "root", Host: "base.example.org", Protocol: "https", RefreshUrl: "<protocol>://<host>/update"})
+
+	assert.Equal(t, &Template{Extends: "base", Host: "variant.example.org", Protocol: "https", RefreshUrl: "<protocol>://<host>/update"}, template)
+}
+
 func mergeTemplateTables() []struct {
 	name           string
 	givenDomain    Domain
@@ -201,3 +208,34 @@ func requiresTemplateTable() []struct {
 		},
 	}
 }
+
+func TestDomain_GetFamilies(t *testing.T) {
+	assert.Equal(t, []string{"ip4", "ip6"}, (&Domain{}).GetFamilies())
+	assert.Equal(t, []string{"ip6"}, (&Domain{Families: []string{"ip6"}}).GetFamilies())
+}
+
+func TestDomain_GetIp6PrefixLength(t *testing.T) {
+	assert.Equal(t, 64, (&Domain{}).GetIp6PrefixLength())
+	assert.Equal(t, 56, (&Domain{Ip6PrefixLength: 56}).GetIp6PrefixLength())
+}
+
+func TestDomain_SplitByFamily(t *testing.T) {
+	domain := Domain{
+		DomainName: "dome",
+		Families:   []string{"ip4", "ip6"},
+		Template:   Template{RefreshUrl: "both", RefreshUrlIp4: "four", UserAgent: "yddns/1.0.1"},
+	}
+
+	domains := domain.SplitByFamily()
+
+	assert.Len(t, domains, 2)
+	assert.Equal(t, &Domain{DomainName: "dome", Families: []string{"ip4"}, Template: Template{RefreshUrl: "four", UserAgent: "yddns/1.0.1"}}, domains[0])
+	assert.Equal(t, &Domain{DomainName: "dome", Families: []string{"ip6"}, Template: Template{RefreshUrl: "both", UserAgent: "yddns/1.0.1"}}, domains[1])
+	assert.Equal(t, "four", domain.RefreshUrlIp4)
+
+	domain.RefreshUrl = ""
+	domains = domain.SplitByFamily()
+
+	assert.Len(t, domains, 1)
+	assert.Equal(t, "four", domains[0].RefreshUrl)
+}
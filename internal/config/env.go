@@ -0,0 +1,118 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/spf13/viper"
+)
+
+const (
+	EnvPrefix       = "YDDNS"
+	EnvFileSuffix   = "_FILE"
+	EnvSourcePrefix = "env:"
+)
+
+var (
+	envConfigKeys      = []string{KeyCacheCreatedExpirySeconds, KeyCacheModifiedExpirySeconds, KeyHistoryMaxAgeSeconds, KeyHistoryMaxEntries, KeyRefreshInterval}
+	envIndexedPattern  = regexp.MustCompile(fmt.Sprintf(`^%s_%s_(\d+)_(.+)$`, EnvPrefix, strings.ToUpper(KeyDomains)))
+	envSingleDomainKey = createEnvName(EnvPrefix, KeyDomainName)
+)
+
+func bindEnvConfig(v *viper.Viper) {
+	v.SetEnvPrefix(EnvPrefix)
+	for _, key := range envConfigKeys {
+		_ = v.BindEnv(key)
+	}
+}
+
+func readEnvDomains() ([]*Domain, []string, error) {
+	var domains []*Domain
+	if os.Getenv(envSingleDomainKey) != "" || os.Getenv(envSingleDomainKey+EnvFileSuffix) != "" {
+		d, err := readEnvDomain(EnvPrefix)
+		if err != nil {
+			return domains, nil, err
+		}
+
+		domains = append(domains, d)
+	}
+
+	domainKeys := getDomainKeys()
+	indexes := map[int]bool{}
+	var unknownKeys []string
+	for _, env := range os.Environ() {
+		name, _, _ := strings.Cut(env, "=")
+		matches := envIndexedPattern.FindStringSubmatch(name)
+		if matches == nil {
+			continue
+		}
+
+		index, _ := strconv.Atoi(matches[1])
+		indexes[index] = true
+
+		key := strings.ToLower(strings.TrimSuffix(matches[2], EnvFileSuffix))
+		if !slices.Contains(domainKeys, key) {
+			unknownKeys = append(unknownKeys, name)
+		}
+	}
+
+	for _, index := range slices.Sorted(maps.Keys(indexes)) {
+		d, err := readEnvDomain(fmt.Sprintf("%s_%s_%d", EnvPrefix, strings.ToUpper(KeyDomains), index))
+		if err != nil {
+			return domains, unknownKeys, err
+		}
+
+		domains = append(domains, d)
+	}
+
+	slices.Sort(unknownKeys)
+
+	return domains, unknownKeys, nil
+}
+
+func readEnvDomain(prefix string) (*Domain, error) {
+	v := viper.New()
+	v.SetEnvPrefix(prefix)
+
+	for _, key := range getDomainKeys() {
+		_ = v.BindEnv(key)
+
+		fileEnv := createEnvName(prefix, key) + EnvFileSuffix
+		file := os.Getenv(fileEnv)
+		if file == "" {
+			continue
+		}
+
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", fileEnv, err)
+		}
+
+		v.Set(key, strings.TrimRight(string(data), "\r\n"))
+	}
+
+	d := &Domain{Source: EnvSourcePrefix + prefix}
+	err := v.Unmarshal(d)
+	if err != nil {
+		return nil, &ValidationError{File: d.Source, Index: -1, Err: err}
+	}
+
+	return d, nil
+}
+
+func getDomainKeys() []string {
+	values := map[string]any{}
+	_ = mapstructure.Decode(Domain{}, &values)
+
+	return slices.Sorted(maps.Keys(values))
+}
+
+func createEnvName(prefix string, key string) string {
+	return fmt.Sprintf("%s_%s", prefix, strings.ToUpper(key))
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadEnvDomains(t *testing.T) {
+	passwordFile := filepath.Join(t.TempDir(), "password")
+	_ = os.WriteFile(passwordFile, []byte("secret\n"), 0600)
+
+	t.Setenv("YDDNS_DOMAIN", "single.example.org")
+	t.Setenv("YDDNS_REFRESH_URL", ":duckdns")
+	t.Setenv("YDDNS_PASSWORD_FILE", passwordFile)
+	t.Setenv("YDDNS_DOMAINS_10_DOMAIN", "ten.example.org")
+	t.Setenv("YDDNS_DOMAINS_2_DOMAIN", "two.example.org")
+	t.Setenv("YDDNS_DOMAINS_2_FAMILIES", "ip4,ip6")
+	t.Setenv("YDDNS_DOMAINS_2_IP6_PREFIX_LENGTH", "56")
+	t.Setenv("YDDNS_DOMAINS_2_SKIP_IF_RESOLVED", "true")
+	t.Setenv("YDDNS_DOMAINS_2_USERNME", "typo")
+
+	domains, unknownKeys, err := readEnvDomains()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"YDDNS_DOMAINS_2_USERNME"}, unknownKeys)
+	assert.Len(t, domains, 3)
+	assert.Equal(t, &Domain{Template: Template{RefreshUrl: ":duckdns"}, AuthPassword: "secret", DomainName: "single.example.org", Source: "env:YDDNS"}, domains[0])
+	assert.Equal(t, &Domain{DomainName: "two.example.org", Families: []string{"ip4", "ip6"}, Ip6PrefixLength: 56, SkipIfResolved: true, Source: "env:YDDNS_DOMAINS_2"}, domains[1])
+	assert.Equal(t, &Domain{DomainName: "ten.example.org", Source: "env:YDDNS_DOMAINS_10"}, domains[2])
+}
+
+func TestReadEnvDomainsWithMissingFile(t *testing.T) {
+	t.Setenv("YDDNS_DOMAIN", "single.example.org")
+	t.Setenv("YDDNS_PASSWORD_FILE", filepath.Join(t.TempDir(), "404"))
+
+	_, _, err := readEnvDomains()
+
+	assert.ErrorContains(t, err, "YDDNS_PASSWORD_FILE: open ")
+}
+
+func TestConfig_NewFileConfigWithEnv(t *testing.T) {
+	Dirs = []string{t.TempDir()}
+	t.Setenv("YDDNS_DOMAIN", "single.example.org")
+	t.Setenv("YDDNS_REFRESH_INTERVAL", "300")
+
+	cfg, err := NewFileConfig("42")
+
+	assert.NoError(t, err)
+
+	assert.Empty(t, cfg.File)
+	assert.Equal(t, 300, cfg.RefreshInterval)
+	assert.Len(t, cfg.Domains, 1)
+	assert.Equal(t, "single.example.org", cfg.Domains[0].DomainName)
+}
+
+func TestConfig_NewFileConfigWithEnvOverride(t *testing.T) {
+	thisDir, _ := filepath.Abs("./")
+	Dirs = []string{filepath.Join(thisDir, "testdata")}
+	t.Setenv("YDDNS_REFRESH_INTERVAL", "300")
+
+	cfg, err := NewFileConfig("42")
+
+	assert.NoError(t, err)
+
+	assert.Equal(t, 300, cfg.RefreshInterval)
+	assert.Len(t, cfg.Domains, 1)
+}
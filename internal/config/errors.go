@@ -0,0 +1,45 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+type NotFoundError struct {
+	File string
+	Dirs []string
+}
+
+func (e *NotFoundError) Error() string {
+	if e.File != "" {
+		return fmt.Sprintf("config file %s not found", e.File)
+	}
+
+	return fmt.Sprintf("no config file found in %s", strings.Join(e.Dirs, ", "))
+}
+
+type ParseError struct {
+	File string
+	Err  error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s is invalid: %s", e.File, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+type TemplateNotFoundError struct {
+	Name       string
+	ExtendedBy string
+}
+
+func (e *TemplateNotFoundError) Error() string {
+	if e.ExtendedBy != "" {
+		return fmt.Sprintf("template \"%s\" extends unknown template \"%s\"", e.ExtendedBy, e.Name)
+	}
+
+	return fmt.Sprintf("template \"%s\" not found", e.Name)
+}
@@ -0,0 +1,173 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var placeholderPattern = regexp.MustCompile(`<([^<>]*)>`)
+
+type ValidationError struct {
+	File   string
+	Domain string
+	Index  int
+	Err    error
+}
+
+func (e *ValidationError) Error() string {
+	if e.File == "" {
+		return e.Err.Error()
+	}
+
+	if e.Index < 0 {
+		return fmt.Sprintf("%s: %s", e.File, e.Err)
+	}
+
+	return fmt.Sprintf("%s: domains[%d] \"%s\": %s", e.File, e.Index, e.Domain, e.Err)
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+func ValidateFileConfig(appVersion string) ([]string, []error) {
+	c, err := NewConfig(appVersion)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	err = c.ReadFile(FilePath)
+	files := slices.DeleteFunc(append([]string{c.File}, c.ConfFiles...), func(f string) bool { return f == "" })
+	for _, d := range c.Domains {
+		if strings.HasPrefix(d.Source, EnvSourcePrefix) && !slices.Contains(files, d.Source) {
+			files = append(files, d.Source)
+		}
+	}
+	if err != nil {
+		var validationErr *ValidationError
+		var parseErr *ParseError
+		var notFoundErr *NotFoundError
+		if !errors.As(err, &validationErr) && !errors.As(err, &parseErr) && !errors.As(err, &notFoundErr) {
+			err = &ValidationError{File: c.File, Index: -1, Err: err}
+		}
+
+		return files, []error{err}
+	}
+
+	var errs []error
+	for _, keyFile := range slices.Sorted(maps.Keys(c.unknownKeys)) {
+		for _, key := range c.unknownKeys[keyFile] {
+			errs = append(errs, &ValidationError{File: keyFile, Index: -1, Err: fmt.Errorf("unknown key \"%s\"", key)})
+		}
+	}
+
+	for _, templateName := range slices.Sorted(maps.Keys(c.Templates)) {
+		if _, err = c.GetTemplate(templateName); err != nil {
+			errs = append(errs, &ValidationError{File: c.TemplateSources[templateName], Index: -1, Err: err})
+		}
+	}
+
+	if len(c.Domains) == 0 {
+		errs = append(errs, &ValidationError{File: c.File, Index: -1, Err: fmt.Errorf("no domains configured")})
+	}
+
+	indexes := createDomainIndexes(c.Domains)
+	for i, d := range c.Domains {
+		for _, err = range c.ValidateDomain(d) {
+			errs = append(errs, &ValidationError{File: d.Source, Domain: d.DomainName, Index: indexes[i], Err: err})
+		}
+	}
+
+	return files, errs
+}
+
+func (c *Config) ValidateDomain(d *Domain) []error {
+	domain := *d
+	err := c.PrepareDomain(&domain)
+	if err != nil {
+		return []error{err}
+	}
+
+	var errs []error
+	if !slices.Contains(SupportedAuthMethods, domain.AuthMethod) {
+		errs = append(errs, createUnsupportedValueError(KeyAuthMethod, domain.AuthMethod, SupportedAuthMethods))
+	}
+
+	if !slices.Contains(SupportedProtocols, domain.Protocol) {
+		errs = append(errs, createUnsupportedValueError(KeyProtocol, domain.Protocol, SupportedProtocols))
+	}
+
+	if !slices.Contains(SupportedRequestMethods, domain.RequestMethod) {
+		errs = append(errs, createUnsupportedValueError(KeyRequestMethod, domain.RequestMethod, SupportedRequestMethods))
+	}
+
+	staticAddresses := [][2]string{{KeyIp4, domain.Ip4Address}, {KeyIp6, domain.Ip6Address}}
+	for _, staticAddress := range staticAddresses {
+		if staticAddress[1] != "" {
+			_, err = domain.ApplyAddressPolicy(staticAddress[0], staticAddress[1])
+			if err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	if domain.RefreshUrl == "" && !domain.HasFamilyRefreshUrls() {
+		errs = append(errs, fmt.Errorf("no refresh url configured"))
+	}
+
+	refreshUrls := [][2]string{{KeyRefreshUrl, domain.RefreshUrl}, {KeyRefreshUrlIp4, domain.RefreshUrlIp4}, {KeyRefreshUrlIp6, domain.RefreshUrlIp6}}
+	for _, refreshUrl := range refreshUrls {
+		if refreshUrl[1] != "" {
+			errs = append(errs, validateRefreshUrl(refreshUrl[0], refreshUrl[1], &domain)...)
+		}
+	}
+
+	return errs
+}
+
+func validateRefreshUrl(key string, refreshUrl string, d *Domain) []error {
+	var errs []error
+	replacements := map[string]string{
+		KeyDomainName: "domain",
+		KeyHost:       d.Host,
+		KeyIp4:        "192.0.2.1",
+		KeyIp6:        "2001:db8::1",
+		KeyPassword:   "password",
+		KeyProtocol:   d.Protocol,
+		KeyUsername:   "username",
+	}
+
+	sampleUrl := placeholderPattern.ReplaceAllStringFunc(refreshUrl, func(placeholder string) string {
+		name := placeholder[1 : len(placeholder)-1]
+		replacement, ok := replacements[name]
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s contains unknown placeholder \"%s\"", key, placeholder))
+		}
+
+		return replacement
+	})
+
+	parsedUrl, err := url.Parse(sampleUrl)
+	if err != nil {
+		return append(errs, fmt.Errorf("%s \"%s\" is malformed: %w", key, refreshUrl, err))
+	}
+
+	if !slices.Contains(SupportedProtocols, parsedUrl.Scheme) {
+		errs = append(errs, fmt.Errorf("%s \"%s\" has no supported scheme [%s]", key, refreshUrl, strings.Join(SupportedProtocols, ", ")))
+	}
+
+	if parsedUrl.Host == "" {
+		errs = append(errs, fmt.Errorf("%s \"%s\" has no host", key, refreshUrl))
+	}
+
+	return errs
+}
+
+func createUnsupportedValueError(key string, value string, supported []string) error {
+	return fmt.Errorf("%s \"%s\" is not supported [%s]", key, value, strings.Join(supported, ", "))
+}
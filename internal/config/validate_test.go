@@ -0,0 +1,119 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const validateConfig = `
+refresh_intervall = 60
+
+[[domains]]
+domain = "valid.drieschel.org"
+refresh_url = ":dyn-alt"
+username = "john"
+
+[[domains]]
+domain = "invalid.drieschel.org"
+auth_method = "digest"
+request_method = "PUT"
+refresh_url = "ftp://<host>/update?ip=<ip4>&name=<hostname>"
+host = "dyn.drieschel.org"
+colour = "blue"
+
+[[domains]]
+domain = "missing.drieschel.org"
+refresh_url = ":missing"
+
+[templates.dyn]
+refresh_url = "<protocol>://<host>/nic/update?hostname=<domain>&myip=<ip4>,<ip6>"
+host = "dyn.drieschel.org"
+
+[templates.dyn-alt]
+extends = "dyn"
+host = "alt.drieschel.org"
+
+[templates.loop-a]
+extends = "loop-b"
+
+[templates.loop-b]
+extends = "loop-a"
+`
+
+func TestValidateFileConfig(t *testing.T) {
+	Dirs = []string{}
+	FilePath = filepath.Join(t.TempDir(), "config.toml")
+	defer func() { FilePath = "" }()
+
+	err := os.WriteFile(FilePath, []byte(validateConfig), 0600)
+	assert.NoError(t, err)
+
+	files, errs := ValidateFileConfig("42")
+	file := FilePath
+
+	assert.Equal(t, []string{FilePath}, files)
+
+	var messages []string
+	for _, err = range errs {
+		messages = append(messages, err.Error())
+	}
+
+	assert.ElementsMatch(t, []string{
+		file + ": unknown key \"refresh_intervall\"",
+		file + ": unknown key \"domains[1].colour\"",
+		file + ": domains[1] \"invalid.drieschel.org\": auth_method \"digest\" is not supported [basic, bearer]",
+		file + ": domains[1] \"invalid.drieschel.org\": request_method \"PUT\" is not supported [GET, POST]",
+		file + ": domains[1] \"invalid.drieschel.org\": refresh_url contains unknown placeholder \"<hostname>\"",
+		file + ": domains[1] \"invalid.drieschel.org\": refresh_url \"ftp://<host>/update?ip=<ip4>&name=<hostname>\" has no supported scheme [http, https]",
+		file + ": domains[2] \"missing.drieschel.org\": template \"missing\" not found",
+		file + ": template \"loop-a\" has cyclic extends (loop-a -> loop-b -> loop-a)",
+		file + ": template \"loop-b\" has cyclic extends (loop-b -> loop-a -> loop-b)",
+	}, messages)
+}
+
+func TestValidateFileConfigWithConfD(t *testing.T) {
+	Dirs = []string{}
+	FilePath = filepath.Join(t.TempDir(), "config.toml")
+	defer func() { FilePath = "" }()
+
+	confFile := filepath.Join(filepath.Dir(FilePath), DirNameConfD, "10-domains.toml")
+	_ = os.MkdirAll(filepath.Dir(confFile), 0755)
+	_ = os.WriteFile(FilePath, []byte(validateConfig), 0600)
+	_ = os.WriteFile(confFile, []byte("refresh_interval = 30\n[[domains]]\ndomain = \"conf.drieschel.org\"\nrefresh_url = \":dyn\"\n[[domains]]\ndomain = \"broken.drieschel.org\"\nrefresh_url = \":missing\""), 0600)
+
+	files, errs := ValidateFileConfig("42")
+
+	assert.Equal(t, []string{FilePath, confFile}, files)
+
+	var messages []string
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+
+	assert.Contains(t, messages, confFile+": unknown key \"refresh_interval\"")
+	assert.Contains(t, messages, confFile+": domains[1] \"broken.drieschel.org\": template \"missing\" not found")
+	assert.NotContains(t, messages, confFile+": domains[0] \"conf.drieschel.org\": template \"dyn\" not found")
+}
+
+func TestConfig_ValidateDomain(t *testing.T) {
+	cfg := &Config{Templates: map[string]*Template{}}
+
+	errs := cfg.ValidateDomain(&Domain{DomainName: "dome", Template: Template{RefreshUrl: "https://dyn.drieschel.org/update?ip=<ip4>"}})
+	assert.Empty(t, errs)
+
+	errs = cfg.ValidateDomain(&Domain{DomainName: "dome", Template: Template{RefreshUrl: "<protocol>://<host>/update"}})
+	assert.EqualError(t, errs[0], "refresh_url \"<protocol>://<host>/update\" has no host")
+
+	errs = cfg.ValidateDomain(&Domain{DomainName: "dome"})
+	assert.EqualError(t, errs[0], "no refresh url configured")
+
+	errs = cfg.ValidateDomain(&Domain{DomainName: "dome", Ip4Address: "192.168.178.2", AddressPolicy: AddressPolicyReject, Template: Template{RefreshUrl: "https://dyn.drieschel.org/update?ip=<ip4>"}})
+	assert.Len(t, errs, 1)
+	assert.EqualError(t, errs[0], "ip4 address of domain \"dome\" rejected by address_policy \"reject\": address 192.168.178.2 is a private address (RFC 1918 / unique local) which is only reachable in the local network")
+
+	errs = cfg.ValidateDomain(&Domain{DomainName: "dome", Ip4Address: "192.168.178.2", Template: Template{RefreshUrl: "https://dyn.drieschel.org/update?ip=<ip4>"}})
+	assert.Empty(t, errs)
+}
@@ -0,0 +1,267 @@
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	FileName = "history.jsonl"
+
+	MaxEntriesDefault    = 10000
+	MaxAgeSecondsDefault = 31536000
+	MaxLineSize          = 1024 * 1024
+
+	ResultUpdated    = "updated"
+	ResultUnverified = "unverified"
+	ResultFailed     = "failed"
+)
+
+type History interface {
+	Add(entry *Entry) error
+	Find(filter Filter) ([]*Entry, error)
+	LastUpdated(domain string, family string) (*Entry, error)
+	Prune() error
+}
+
+type Entry struct {
+	Time       time.Time `json:"time"`
+	Domain     string    `json:"domain"`
+	Family     string    `json:"family"`
+	OldAddress string    `json:"old_address"`
+	NewAddress string    `json:"new_address"`
+	Result     string    `json:"result"`
+	Message    string    `json:"message"`
+}
+
+func NewEntry(domain string, family string, oldAddress string, newAddress string, result string, message string) *Entry {
+	return &Entry{Time: time.Now(), Domain: domain, Family: family, OldAddress: oldAddress, NewAddress: newAddress, Result: result, Message: message}
+}
+
+type Filter struct {
+	Domain string
+	Family string
+	Since  time.Time
+	Until  time.Time
+}
+
+func (f Filter) Matches(entry *Entry) bool {
+	if f.Domain != "" && f.Domain != entry.Domain {
+		return false
+	}
+
+	if f.Family != "" && f.Family != entry.Family {
+		return false
+	}
+
+	if !f.Since.IsZero() && entry.Time.Before(f.Since) {
+		return false
+	}
+
+	if !f.Until.IsZero() && entry.Time.After(f.Until) {
+		return false
+	}
+
+	return true
+}
+
+type WarningHandler func(warning error)
+
+type FileHistory struct {
+	historyDir     string
+	maxEntries     int
+	maxAgeSeconds  int
+	warningHandler WarningHandler
+}
+
+func NewFileHistoryWithDefaultValues(historyDir string) *FileHistory {
+	return NewFileHistory(historyDir, MaxEntriesDefault, MaxAgeSecondsDefault)
+}
+
+func NewFileHistory(historyDir string, maxEntries int, maxAgeSeconds int) *FileHistory {
+	return &FileHistory{historyDir: historyDir, maxEntries: maxEntries, maxAgeSeconds: maxAgeSeconds}
+}
+
+func (h *FileHistory) SetWarningHandler(handler WarningHandler) {
+	h.warningHandler = handler
+}
+
+func (h *FileHistory) Add(entry *Entry) error {
+	err := os.MkdirAll(h.historyDir, 0755)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(h.createFilePath(), os.O_APPEND|os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	terminated, err := isTerminated(file)
+	if err != nil {
+		return err
+	}
+
+	if !terminated {
+		data = append([]byte{'\n'}, data...)
+	}
+
+	_, err = file.Write(append(data, '\n'))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (h *FileHistory) Find(filter Filter) ([]*Entry, error) {
+	entries, err := h.readEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	var found []*Entry
+	for _, entry := range entries {
+		if filter.Matches(entry) {
+			found = append(found, entry)
+		}
+	}
+
+	return found, nil
+}
+
+func (h *FileHistory) LastUpdated(domain string, family string) (*Entry, error) {
+	entries, err := h.Find(Filter{Domain: domain, Family: family})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].Result == ResultUpdated {
+			return entries[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (h *FileHistory) Prune() error {
+	entries, skipped, err := h.readEntriesAndCountSkipped()
+	if err != nil {
+		return err
+	}
+
+	retained := entries
+	if h.maxAgeSeconds > 0 {
+		oldest := time.Now().Add(-time.Duration(h.maxAgeSeconds) * time.Second)
+		retained = []*Entry{}
+		for _, entry := range entries {
+			if !entry.Time.Before(oldest) {
+				retained = append(retained, entry)
+			}
+		}
+	}
+
+	if h.maxEntries > 0 && len(retained) > h.maxEntries {
+		retained = retained[len(retained)-h.maxEntries:]
+	}
+
+	if len(retained) == len(entries) && skipped == 0 {
+		return nil
+	}
+
+	var buffer bytes.Buffer
+	for _, entry := range retained {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+
+		buffer.Write(append(data, '\n'))
+	}
+
+	filePath := h.createFilePath()
+	tmpFilePath := filePath + ".tmp"
+	err = os.WriteFile(tmpFilePath, buffer.Bytes(), 0644)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmpFilePath, filePath)
+}
+
+func (h *FileHistory) readEntries() ([]*Entry, error) {
+	entries, _, err := h.readEntriesAndCountSkipped()
+
+	return entries, err
+}
+
+func (h *FileHistory) readEntriesAndCountSkipped() ([]*Entry, int, error) {
+	filePath := h.createFilePath()
+	file, err := os.Open(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*Entry{}, 0, nil
+		}
+
+		return nil, 0, err
+	}
+
+	defer file.Close()
+
+	var entries []*Entry
+	skipped := 0
+	lineNumber := 0
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), MaxLineSize)
+	for scanner.Scan() {
+		lineNumber++
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		entry := &Entry{}
+		err = json.Unmarshal(line, entry)
+		if err != nil {
+			if h.warningHandler != nil {
+				h.warningHandler(fmt.Errorf("skipping invalid line %d of %s: %w", lineNumber, filePath, err))
+			}
+
+			skipped++
+			continue
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, skipped, scanner.Err()
+}
+
+func isTerminated(file *os.File) (bool, error) {
+	info, err := file.Stat()
+	if err != nil || info.Size() == 0 {
+		return true, err
+	}
+
+	last := make([]byte, 1)
+	_, err = file.ReadAt(last, info.Size()-1)
+
+	return last[0] == '\n', err
+}
+
+func (h *FileHistory) createFilePath() string {
+	return filepath.Join(h.historyDir, FileName)
+}
@@ -0,0 +1,129 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileHistory_AddAndFind(t *testing.T) {
+	h := NewFileHistoryWithDefaultValues(t.TempDir())
+
+	assert.NoError(t, h.Add(&Entry{Time: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC), Domain: "a.tld", Family: "ip4", NewAddress: "1.1.1.1", Result: ResultUpdated}))
+	assert.NoError(t, h.Add(&Entry{Time: time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC), Domain: "b.tld", Family: "ip4", NewAddress: "2.2.2.2", Result: ResultUpdated}))
+	assert.NoError(t, h.Add(&Entry{Time: time.Date(2025, 1, 3, 12, 0, 0, 0, time.UTC), Domain: "a.tld", Family: "ip6", NewAddress: "::1", Result: ResultFailed}))
+
+	entries, err := h.Find(Filter{})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 3)
+
+	entries, err = h.Find(Filter{Domain: "a.tld"})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+
+	entries, err = h.Find(Filter{Since: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), Until: time.Date(2025, 1, 2, 23, 0, 0, 0, time.UTC)})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "b.tld", entries[0].Domain)
+}
+
+func TestFileHistory_FindWithoutFile(t *testing.T) {
+	h := NewFileHistoryWithDefaultValues(t.TempDir())
+
+	entries, err := h.Find(Filter{})
+
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestFileHistory_LastUpdated(t *testing.T) {
+	h := NewFileHistoryWithDefaultValues(t.TempDir())
+
+	assert.NoError(t, h.Add(NewEntry("a.tld", "ip4", "", "1.1.1.1", ResultUpdated, "")))
+	assert.NoError(t, h.Add(NewEntry("a.tld", "ip4", "1.1.1.1", "2.2.2.2", ResultUpdated, "")))
+	assert.NoError(t, h.Add(NewEntry("a.tld", "ip4", "2.2.2.2", "3.3.3.3", ResultFailed, "")))
+
+	entry, err := h.LastUpdated("a.tld", "ip4")
+	assert.NoError(t, err)
+	assert.Equal(t, "2.2.2.2", entry.NewAddress)
+
+	entry, err = h.LastUpdated("a.tld", "ip6")
+	assert.NoError(t, err)
+	assert.Nil(t, entry)
+}
+
+func TestFileHistory_Prune(t *testing.T) {
+	dir := t.TempDir()
+	h := NewFileHistory(dir, 2, 3600)
+
+	assert.NoError(t, h.Add(&Entry{Time: time.Now().Add(-2 * time.Hour), Domain: "expired.tld"}))
+	assert.NoError(t, h.Add(&Entry{Time: time.Now(), Domain: "first.tld"}))
+	assert.NoError(t, h.Add(&Entry{Time: time.Now(), Domain: "second.tld"}))
+	assert.NoError(t, h.Add(&Entry{Time: time.Now(), Domain: "third.tld"}))
+
+	assert.NoError(t, h.Prune())
+
+	entries, err := h.Find(Filter{})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "second.tld", entries[0].Domain)
+	assert.Equal(t, "third.tld", entries[1].Domain)
+}
+
+func TestFileHistory_AddAfterTruncatedLine(t *testing.T) {
+	dir := t.TempDir()
+	h := NewFileHistoryWithDefaultValues(dir)
+
+	assert.NoError(t, h.Add(NewEntry("a.tld", "ip4", "", "1.1.1.1", ResultUpdated, "")))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, FileName), append(readFile(t, dir), []byte(`{"time":"2025-01-0`)...), 0644))
+	assert.NoError(t, h.Add(NewEntry("b.tld", "ip4", "", "2.2.2.2", ResultUpdated, "")))
+
+	entries, err := h.Find(Filter{})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "a.tld", entries[0].Domain)
+	assert.Equal(t, "b.tld", entries[1].Domain)
+}
+
+func TestFileHistory_PruneRemovesInvalidLines(t *testing.T) {
+	dir := t.TempDir()
+	h := NewFileHistoryWithDefaultValues(dir)
+
+	var warnings []string
+	h.SetWarningHandler(func(warning error) {
+		warnings = append(warnings, warning.Error())
+	})
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, FileName), []byte("invalid\n"), 0644))
+	assert.NoError(t, h.Add(NewEntry("a.tld", "ip4", "", "1.1.1.1", ResultUpdated, "")))
+
+	assert.NoError(t, h.Prune())
+	assert.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0], "skipping invalid line 1 of "+filepath.Join(dir, FileName))
+
+	assert.NotContains(t, string(readFile(t, dir)), "invalid")
+	entries, err := h.Find(Filter{})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestFileHistory_FindWithLongLine(t *testing.T) {
+	h := NewFileHistoryWithDefaultValues(t.TempDir())
+
+	assert.NoError(t, h.Add(NewEntry("a.tld", "ip4", "", "1.1.1.1", ResultFailed, strings.Repeat("x", 100000))))
+
+	entries, err := h.Find(Filter{})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func readFile(t *testing.T, dir string) []byte {
+	data, err := os.ReadFile(filepath.Join(dir, FileName))
+	assert.NoError(t, err)
+
+	return data
+}
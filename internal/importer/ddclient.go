@@ -0,0 +1,221 @@
+package importer
+
+import (
+	"bufio"
+	"io"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/drieschel/yddns/internal/config"
+)
+
+const (
+	ddclientDefaultProtocol = "dyndns2"
+	ddclientDefaultServer   = "members.dyndns.org"
+)
+
+var (
+	ddclientOptionPattern  = regexp.MustCompile(`^\s*([A-Za-z0-9_-]+)\s*=\s*("[^"]*"|'[^']*'|[^\s,]*)\s*,?`)
+	ddclientHostsPattern   = regexp.MustCompile(`[\s,]+`)
+	ddclientIgnoredOptions = []string{"cache", "daemon", "debug", "foreground", "mail", "mail-failure", "pid", "quiet", "syslog", "timeout", "verbose", "web", "web-skip", "webv4", "webv4-skip", "webv6", "webv6-skip"}
+	ddclientMappedOptions  = []string{"if", "ifv4", "ifv6", "login", "password", "protocol", "server", "ssl", "use", "usev4", "usev6"}
+	ddclientProtocols      = map[string]string{
+		"duckdns":   TemplateDuckdns,
+		"dyndns2":   TemplateDyndns2,
+		"freedns":   TemplateFreedns,
+		"inwx":      TemplateInwx,
+		"namecheap": TemplateNamecheap,
+		"noip":      TemplateNoip,
+		"ovh":       TemplateOvh,
+	}
+)
+
+func ParseDdclient(r io.Reader) (*Result, error) {
+	result := &Result{}
+	globals := map[string]string{}
+	reportedOptions := map[string]bool{}
+
+	scanner := bufio.NewScanner(r)
+	lineNumber, startLine := 0, 0
+	var statement string
+	for scanner.Scan() {
+		lineNumber++
+		line := stripDdclientComment(scanner.Text())
+		if statement == "" {
+			startLine = lineNumber
+		}
+
+		trimmed := strings.TrimRight(line, " \t")
+		if strings.HasSuffix(trimmed, "\\") {
+			statement += strings.TrimSuffix(trimmed, "\\") + " "
+			continue
+		}
+
+		statement += line
+		options, hosts := parseDdclientStatement(statement)
+		statement = ""
+
+		for _, key := range slices.Sorted(maps.Keys(options)) {
+			if !reportedOptions[key] && !slices.Contains(ddclientMappedOptions, key) && !slices.Contains(ddclientIgnoredOptions, key) {
+				reportedOptions[key] = true
+				result.addProblem(startLine, "", "option \"%s\" is not supported and ignored", key)
+			}
+		}
+
+		if len(hosts) == 0 {
+			for key, value := range options {
+				globals[key] = value
+			}
+
+			if daemon, exist := options["daemon"]; exist {
+				interval, err := parseInterval(daemon)
+				if err != nil {
+					result.addProblem(startLine, "", "%s", err)
+				}
+
+				result.RefreshInterval = interval
+			}
+
+			continue
+		}
+
+		entryOptions := map[string]string{}
+		for key, value := range globals {
+			entryOptions[key] = value
+		}
+
+		for key, value := range options {
+			entryOptions[key] = value
+		}
+
+		for _, host := range hosts {
+			d := createDdclientDomain(result, startLine, host, entryOptions)
+			if d != nil {
+				result.Domains = append(result.Domains, d)
+			}
+		}
+	}
+
+	return result, scanner.Err()
+}
+
+func parseDdclientStatement(statement string) (map[string]string, []string) {
+	options := map[string]string{}
+	for {
+		matches := ddclientOptionPattern.FindStringSubmatch(statement)
+		if matches == nil {
+			break
+		}
+
+		options[strings.ToLower(matches[1])] = strings.Trim(matches[2], `"'`)
+		statement = statement[len(matches[0]):]
+	}
+
+	var hosts []string
+	for _, host := range ddclientHostsPattern.Split(statement, -1) {
+		if host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+
+	return options, hosts
+}
+
+func stripDdclientComment(line string) string {
+	var quote rune
+	for i, char := range line {
+		switch {
+		case quote != 0 && char == quote:
+			quote = 0
+		case quote == 0 && (char == '"' || char == '\''):
+			quote = char
+		case quote == 0 && char == '#':
+			return line[:i]
+		}
+	}
+
+	return line
+}
+
+func createDdclientDomain(result *Result, line int, host string, options map[string]string) *config.Domain {
+	protocol := options["protocol"]
+	if protocol == "" {
+		protocol = ddclientDefaultProtocol
+	}
+
+	template, ok := ddclientProtocols[protocol]
+	if !ok {
+		result.addProblem(line, host, "%s", createUnsupportedProviderMessage("protocol", protocol))
+
+		return nil
+	}
+
+	var d *config.Domain
+	switch template {
+	case TemplateDyndns2:
+		server := options["server"]
+		if server == "" {
+			server = ddclientDefaultServer
+		}
+
+		if serverTemplate, exist := dyndns2ServerTemplates[server]; exist {
+			template = serverTemplate
+		}
+
+		d = createTemplateDomain(template, host, options["login"], options["password"])
+		if template == TemplateDyndns2 && server != ddclientDefaultServer {
+			d.Host = server
+		}
+
+	case TemplateNamecheap:
+		d = createNamecheapDomain(options["login"], host, options["password"])
+
+	default:
+		d = createTemplateDomain(template, host, options["login"], options["password"])
+	}
+
+	if template == TemplateFreedns {
+		result.addProblem(line, host, "password has to be replaced by the update token of the record")
+	}
+
+	if options["ssl"] == "no" {
+		d.Protocol = config.ProtocolHttp
+	}
+
+	usev4 := options["usev4"]
+	if usev4 == "" {
+		usev4 = options["use"]
+	}
+
+	if usev4 != "" && usev4 != "web" && usev4 != "webv4" {
+		result.addProblem(line, host, "ipv4 detection \"%s\" is not supported, the wan address is used", usev4)
+	}
+
+	switch usev6 := options["usev6"]; usev6 {
+	case "":
+		d.Families = []string{config.KeyIp4}
+
+	case "if", "ifv6":
+		d.Ip6Interface = options["ifv6"]
+		if d.Ip6Interface == "" {
+			d.Ip6Interface = options["if"]
+		}
+
+		if d.Ip6Interface == "" {
+			result.addProblem(line, host, "ipv6 detection \"%s\" has no interface, the wan address is used", usev6)
+		}
+
+	case "web", "webv6":
+
+	default:
+		result.addProblem(line, host, "ipv6 detection \"%s\" is not supported, the wan address is used", usev6)
+	}
+
+	if options["usev6"] != "" && options["usev4"] == "" && options["use"] == "" {
+		d.Families = []string{config.KeyIp6}
+	}
+
+	return d
+}
@@ -0,0 +1,105 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/drieschel/yddns/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDdclient(t *testing.T) {
+	tests := []struct {
+		name             string
+		input            string
+		expectedDomains  []*config.Domain
+		expectedProblems []string
+		expectedInterval int
+	}{
+		{
+			name: "dyndns2 with globals and continuation",
+			input: `daemon=300 # check every 5 minutes
+ssl=yes
+protocol=dyndns2, \
+server=members.dyndns.org, \
+login=jane, password='se#cret' \
+home.example.org,office.example.org`,
+			expectedDomains: []*config.Domain{
+				{Template: config.Template{RefreshUrl: ":dyndns2"}, AuthUser: "jane", AuthPassword: "se#cret", DomainName: "home.example.org", Families: []string{"ip4"}},
+				{Template: config.Template{RefreshUrl: ":dyndns2"}, AuthUser: "jane", AuthPassword: "se#cret", DomainName: "office.example.org", Families: []string{"ip4"}},
+			},
+			expectedInterval: 300,
+		},
+		{
+			name:  "dyndns2 server of a known provider",
+			input: `protocol=dyndns2, server=dynv6.com, login=none, password=token, usev4=webv4, usev6=webv6 home.dynv6.net`,
+			expectedDomains: []*config.Domain{
+				{Template: config.Template{RefreshUrl: ":dynv6"}, AuthUser: "none", AuthPassword: "token", DomainName: "home.dynv6.net"},
+			},
+		},
+		{
+			name:  "dyndns2 unknown server with ipv6 interface",
+			input: "ssl=no\nprotocol=dyndns2, server=dyn.example.com, usev6=ifv6, ifv6=eth0, login=jane, password=secret home.example.org",
+			expectedDomains: []*config.Domain{
+				{Template: config.Template{Host: "dyn.example.com", Protocol: "http", RefreshUrl: ":dyndns2"}, AuthUser: "jane", AuthPassword: "secret", DomainName: "home.example.org", Families: []string{"ip6"}, Ip6Interface: "eth0"},
+			},
+		},
+		{
+			name:  "ipv6 interface of the shared if option",
+			input: "protocol=dyndns2, usev4=webv4, usev6=if, if=eth0, login=jane, password=secret home.example.org\nprotocol=dyndns2, usev6=ifv6, login=jane, password=secret office.example.org",
+			expectedDomains: []*config.Domain{
+				{Template: config.Template{RefreshUrl: ":dyndns2"}, AuthUser: "jane", AuthPassword: "secret", DomainName: "home.example.org", Ip6Interface: "eth0"},
+				{Template: config.Template{RefreshUrl: ":dyndns2"}, AuthUser: "jane", AuthPassword: "secret", DomainName: "office.example.org", Families: []string{"ip6"}},
+			},
+			expectedProblems: []string{
+				`line 2: "office.example.org": ipv6 detection "ifv6" has no interface, the wan address is used`,
+			},
+		},
+		{
+			name:  "cloudflare",
+			input: "protocol=cloudflare, zone=example.org, login=token, password=secret home.example.org",
+			expectedProblems: []string{
+				`line 1: option "zone" is not supported and ignored`,
+				`line 1: "home.example.org": protocol "cloudflare" is not supported, its api requires zone and record ids and a json request body, which a refresh url cannot express`,
+			},
+		},
+		{
+			name:  "namecheap records",
+			input: "protocol=namecheap, login=example.org, password=secret @, www",
+			expectedDomains: []*config.Domain{
+				{Template: config.Template{RefreshUrl: ":namecheap"}, AuthUser: "@", AuthPassword: "secret", DomainName: "example.org", Families: []string{"ip4"}},
+				{Template: config.Template{Host: "dynamicdns.park-your-domain.com", RefreshUrl: "<protocol>://<host>/update?host=www&domain=example.org&password=<password>&ip=<ip4>"}, AuthPassword: "secret", DomainName: "www.example.org", Families: []string{"ip4"}},
+			},
+		},
+		{
+			name:  "unsupported protocol, detection and option",
+			input: "use=if, if=eth0\nzone=example.org\nprotocol=zoneedit1, login=jane, password=secret home.example.org\nprotocol=freedns, login=jane, password=secret free.example.org",
+			expectedDomains: []*config.Domain{
+				{Template: config.Template{RefreshUrl: ":freedns"}, AuthUser: "jane", AuthPassword: "secret", DomainName: "free.example.org", Families: []string{"ip4"}},
+			},
+			expectedProblems: []string{
+				`line 2: option "zone" is not supported and ignored`,
+				`line 3: "home.example.org": protocol "zoneedit1" is not supported`,
+				`line 4: "free.example.org": password has to be replaced by the update token of the record`,
+				`line 4: "free.example.org": ipv4 detection "if" is not supported, the wan address is used`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseDdclient(strings.NewReader(tt.input))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedDomains, result.Domains)
+			assert.Equal(t, tt.expectedInterval, result.RefreshInterval)
+
+			var problems []string
+			for _, problem := range result.Problems {
+				problems = append(problems, problem.String())
+			}
+
+			assert.Equal(t, tt.expectedProblems, problems)
+		})
+	}
+}
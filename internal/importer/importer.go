@@ -0,0 +1,121 @@
+package importer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/drieschel/yddns/internal/config"
+)
+
+const (
+	TemplateDdnss     = "ddnss"
+	TemplateDesec     = "desec"
+	TemplateDuckdns   = "duckdns"
+	TemplateDyndns2   = "dyndns2"
+	TemplateDynu      = "dynu"
+	TemplateDynv6     = "dynv6"
+	TemplateFreedns   = "freedns"
+	TemplateHe        = "he"
+	TemplateInwx      = "inwx"
+	TemplateNamecheap = "namecheap"
+	TemplateNoip      = "noip"
+	TemplateOvh       = "ovh"
+	TemplateSelfhost  = "selfhost"
+	TemplateSpdyn     = "spdyn"
+	TemplateStrato    = "strato"
+
+	namecheapRefreshUrl = "<protocol>://<host>/update?host=%s&domain=%s&password=<password>&ip=<ip4>"
+	namecheapRecordRoot = "@"
+
+	cloudflareUnsupportedReason = "its api requires zone and record ids and a json request body, which a refresh url cannot express"
+)
+
+var unsupportedProviderReasons = map[string]string{
+	"cloudflare":     cloudflareUnsupportedReason,
+	"cloudflare.com": cloudflareUnsupportedReason,
+}
+
+var dyndns2ServerTemplates = map[string]string{
+	"api.dynu.com":        TemplateDynu,
+	"carol.selfhost.de":   TemplateSelfhost,
+	"ddnss.de":            TemplateDdnss,
+	"dyn.dns.he.net":      TemplateHe,
+	"dyndns.inwx.com":     TemplateInwx,
+	"dyndns.strato.com":   TemplateStrato,
+	"dynupdate.no-ip.com": TemplateNoip,
+	"dynv6.com":           TemplateDynv6,
+	"members.dyndns.org":  TemplateDyndns2,
+	"update.dedyn.io":     TemplateDesec,
+	"update.spdyn.de":     TemplateSpdyn,
+	"www.ovh.com":         TemplateOvh,
+}
+
+type Problem struct {
+	Line    int
+	Host    string
+	Message string
+}
+
+func (p *Problem) String() string {
+	if p.Host == "" {
+		return fmt.Sprintf("line %d: %s", p.Line, p.Message)
+	}
+
+	return fmt.Sprintf("line %d: \"%s\": %s", p.Line, p.Host, p.Message)
+}
+
+type Result struct {
+	Domains         []*config.Domain
+	Problems        []*Problem
+	RefreshInterval int
+}
+
+func (r *Result) addProblem(line int, host string, format string, args ...any) {
+	r.Problems = append(r.Problems, &Problem{Line: line, Host: host, Message: fmt.Sprintf(format, args...)})
+}
+
+func createUnsupportedProviderMessage(kind string, name string) string {
+	if reason, exist := unsupportedProviderReasons[name]; exist {
+		return fmt.Sprintf("%s \"%s\" is not supported, %s", kind, name, reason)
+	}
+
+	return fmt.Sprintf("%s \"%s\" is not supported", kind, name)
+}
+
+func createTemplateDomain(template string, host string, user string, password string) *config.Domain {
+	return &config.Domain{
+		Template:     config.Template{RefreshUrl: config.RefreshUrlTemplatePrefix + template},
+		AuthUser:     user,
+		AuthPassword: password,
+		DomainName:   host,
+	}
+}
+
+func createNamecheapDomain(zone string, record string, password string) *config.Domain {
+	if record == namecheapRecordRoot || record == zone {
+		return createTemplateDomain(TemplateNamecheap, zone, namecheapRecordRoot, password)
+	}
+
+	record = strings.TrimSuffix(record, "."+zone)
+	d := createTemplateDomain(TemplateNamecheap, fmt.Sprintf("%s.%s", record, zone), "", password)
+	d.Host = "dynamicdns.park-your-domain.com"
+	d.RefreshUrl = fmt.Sprintf(namecheapRefreshUrl, record, zone)
+	d.Families = []string{config.KeyIp4}
+
+	return d
+}
+
+func parseInterval(value string) (int, error) {
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return seconds, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("interval \"%s\" is invalid", value)
+	}
+
+	return int(duration.Seconds()), nil
+}
@@ -0,0 +1,269 @@
+package importer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/drieschel/yddns/internal/config"
+)
+
+const (
+	inadynSectionCustom   = "custom"
+	inadynSectionProvider = "provider"
+	inadynFamilyIp6       = "ipv6"
+)
+
+var (
+	inadynIgnoredOptions = []string{"allow-ipv6", "broken-rtc", "ca-trust-file", "forced-update", "iface", "include", "secure-ssl", "user-agent", "verify-address"}
+	inadynProviders      = map[string]string{
+		"ddnss.de":           TemplateDdnss,
+		"dedyn.io":           TemplateDesec,
+		"desec.io":           TemplateDesec,
+		"duckdns.org":        TemplateDuckdns,
+		"dyndns.org":         TemplateDyndns2,
+		"dynu.com":           TemplateDynu,
+		"dynv6.com":          TemplateDynv6,
+		"freedns.afraid.org": TemplateFreedns,
+		"he.net":             TemplateHe,
+		"no-ip.com":          TemplateNoip,
+		"ovh.com":            TemplateOvh,
+		"selfhost.de":        TemplateSelfhost,
+		"spdyn.de":           TemplateSpdyn,
+		"strato.com":         TemplateStrato,
+	}
+	inadynCustomPlaceholders = strings.NewReplacer("%u", "<username>", "%p", "<password>", "%h", "<domain>")
+	inadynSectionOptions     = []string{"ddns-path", "ddns-server", "hostname", "password", "ssl", "username"}
+)
+
+type inadynToken struct {
+	line   int
+	value  string
+	quoted bool
+}
+
+type inadynSection struct {
+	kind    string
+	name    string
+	line    int
+	options map[string][]string
+	lines   map[string]int
+}
+
+func ParseInadyn(r io.Reader) (*Result, error) {
+	tokens, err := tokenizeInadyn(r)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{}
+	for i := 0; i < len(tokens); {
+		token := tokens[i]
+		if !token.quoted && (token.value == inadynSectionCustom || token.value == inadynSectionProvider) && i+2 < len(tokens) && tokens[i+2].value == "{" {
+			section := &inadynSection{kind: token.value, name: tokens[i+1].value, line: token.line, options: map[string][]string{}, lines: map[string]int{}}
+			i, err = parseInadynOptions(tokens, i+3, section)
+			if err != nil {
+				return nil, err
+			}
+
+			result.Domains = append(result.Domains, createInadynDomains(result, section)...)
+			continue
+		}
+
+		global := &inadynSection{options: map[string][]string{}, lines: map[string]int{}}
+		i, err = parseInadynOption(tokens, i, global)
+		if err != nil {
+			return nil, err
+		}
+
+		for key, values := range global.options {
+			switch {
+			case key == "period":
+				interval, err := parseInterval(strings.Join(values, ""))
+				if err != nil {
+					result.addProblem(global.lines[key], "", "%s", err)
+				}
+
+				result.RefreshInterval = interval
+
+			case !slices.Contains(inadynIgnoredOptions, key) && !strings.HasPrefix(key, "cache-") && !strings.HasPrefix(key, "startup-"):
+				result.addProblem(global.lines[key], "", "option \"%s\" is not supported and ignored", key)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func tokenizeInadyn(r io.Reader) ([]*inadynToken, error) {
+	var tokens []*inadynToken
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+		for i := 0; i < len(line); i++ {
+			char := line[i]
+			switch {
+			case char == '#':
+				i = len(line)
+
+			case char == ' ' || char == '\t' || char == '\r':
+
+			case strings.IndexByte("{}=,", char) >= 0:
+				tokens = append(tokens, &inadynToken{line: lineNumber, value: string(char)})
+
+			case char == '"' || char == '\'':
+				end := strings.IndexByte(line[i+1:], char)
+				if end < 0 {
+					return nil, fmt.Errorf("line %d: unterminated quoted string", lineNumber)
+				}
+
+				tokens = append(tokens, &inadynToken{line: lineNumber, value: line[i+1 : i+1+end], quoted: true})
+				i += end + 1
+
+			default:
+				end := strings.IndexAny(line[i:], " \t\r#{}=,\"'")
+				if end < 0 {
+					end = len(line) - i
+				}
+
+				tokens = append(tokens, &inadynToken{line: lineNumber, value: line[i : i+end]})
+				i += end - 1
+			}
+		}
+	}
+
+	return tokens, scanner.Err()
+}
+
+func parseInadynOptions(tokens []*inadynToken, i int, section *inadynSection) (int, error) {
+	var err error
+	for i < len(tokens) {
+		if tokens[i].value == "}" && !tokens[i].quoted {
+			return i + 1, nil
+		}
+
+		i, err = parseInadynOption(tokens, i, section)
+		if err != nil {
+			return i, err
+		}
+	}
+
+	return i, fmt.Errorf("line %d: %s \"%s\" is not closed", section.line, section.kind, section.name)
+}
+
+func parseInadynOption(tokens []*inadynToken, i int, section *inadynSection) (int, error) {
+	key := tokens[i]
+	if key.quoted || i+2 >= len(tokens) || tokens[i+1].value != "=" {
+		return i, fmt.Errorf("line %d: expected \"%s = <value>\"", key.line, key.value)
+	}
+
+	i += 2
+	var values []string
+	if tokens[i].value == "{" && !tokens[i].quoted {
+		for i++; i < len(tokens) && (tokens[i].value != "}" || tokens[i].quoted); i++ {
+			if tokens[i].value != "," || tokens[i].quoted {
+				values = append(values, tokens[i].value)
+			}
+		}
+
+		if i == len(tokens) {
+			return i, fmt.Errorf("line %d: list of \"%s\" is not closed", key.line, key.value)
+		}
+	} else {
+		values = append(values, tokens[i].value)
+	}
+
+	name := strings.ToLower(key.value)
+	section.options[name] = values
+	section.lines[name] = key.line
+
+	return i + 1, nil
+}
+
+func createInadynDomains(result *Result, section *inadynSection) []*config.Domain {
+	name, _, _ := strings.Cut(section.name, ":")
+	family := config.KeyIp4
+	if prefix, provider, found := strings.Cut(name, "@"); found {
+		if prefix == inadynFamilyIp6 {
+			family = config.KeyIp6
+		}
+
+		name = provider
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(section.options)) {
+		if !slices.Contains(inadynSectionOptions, key) && !slices.Contains(inadynIgnoredOptions, key) && !strings.HasPrefix(key, "checkip-") && !strings.HasPrefix(key, "ddns-response") {
+			result.addProblem(section.lines[key], "", "option \"%s\" is not supported and ignored", key)
+		}
+	}
+
+	hosts := section.options["hostname"]
+	if len(hosts) == 0 {
+		result.addProblem(section.line, "", "%s \"%s\" has no hostname", section.kind, section.name)
+
+		return nil
+	}
+
+	template, ok := inadynProviders[name]
+	if section.kind == inadynSectionProvider && !ok {
+		for _, host := range hosts {
+			result.addProblem(section.line, host, "%s", createUnsupportedProviderMessage("provider", name))
+		}
+
+		return nil
+	}
+
+	username := strings.Join(section.options["username"], "")
+	password := strings.Join(section.options["password"], "")
+
+	var domains []*config.Domain
+	for _, host := range hosts {
+		var d *config.Domain
+		if section.kind == inadynSectionCustom {
+			d = createInadynCustomDomain(result, section, host, username, password, family)
+			if d == nil {
+				continue
+			}
+		} else {
+			d = createTemplateDomain(template, host, username, password)
+		}
+
+		if template == TemplateFreedns {
+			result.addProblem(section.line, host, "password has to be replaced by the update token of the record")
+		}
+
+		if ssl := section.options["ssl"]; len(ssl) > 0 && ssl[0] == "false" {
+			d.Protocol = config.ProtocolHttp
+		}
+
+		d.Families = []string{family}
+		domains = append(domains, d)
+	}
+
+	return domains
+}
+
+func createInadynCustomDomain(result *Result, section *inadynSection, host string, username string, password string, family string) *config.Domain {
+	server := strings.Join(section.options["ddns-server"], "")
+	path := strings.Join(section.options["ddns-path"], "")
+	if server == "" || path == "" {
+		result.addProblem(section.line, host, "custom provider requires ddns-server and ddns-path")
+
+		return nil
+	}
+
+	path = inadynCustomPlaceholders.Replace(path)
+	path = strings.ReplaceAll(path, "%i", fmt.Sprintf("<%s>", family))
+
+	return &config.Domain{
+		Template:     config.Template{Host: server, RefreshUrl: "<protocol>://<host>" + path},
+		AuthUser:     username,
+		AuthPassword: password,
+		DomainName:   host,
+	}
+}
@@ -0,0 +1,115 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/drieschel/yddns/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseInadyn(t *testing.T) {
+	tests := []struct {
+		name             string
+		input            string
+		expectedDomains  []*config.Domain
+		expectedProblems []string
+		expectedInterval int
+		expectedErr      string
+	}{
+		{
+			name: "providers",
+			input: `# inadyn.conf
+period = 600
+allow-ipv6 = true
+
+provider default@dyndns.org {
+    username = jane
+    password = "se#cret"
+    hostname = { "home.example.org", "office.example.org" }
+}
+
+provider ipv6@dynv6.com:2 {
+    username = none
+    password = token
+    hostname = home.dynv6.net
+    ssl = false
+}`,
+			expectedDomains: []*config.Domain{
+				{Template: config.Template{RefreshUrl: ":dyndns2"}, AuthUser: "jane", AuthPassword: "se#cret", DomainName: "home.example.org", Families: []string{"ip4"}},
+				{Template: config.Template{RefreshUrl: ":dyndns2"}, AuthUser: "jane", AuthPassword: "se#cret", DomainName: "office.example.org", Families: []string{"ip4"}},
+				{Template: config.Template{Protocol: "http", RefreshUrl: ":dynv6"}, AuthUser: "none", AuthPassword: "token", DomainName: "home.dynv6.net", Families: []string{"ip6"}},
+			},
+			expectedInterval: 600,
+		},
+		{
+			name: "custom provider",
+			input: `custom example:1 {
+    username    = jane
+    password    = secret
+    ddns-server = "dyn.example.com"
+    ddns-path   = "/update?user=%u&pass=%p&host=%h&ip=%i"
+    hostname    = home.example.org
+}`,
+			expectedDomains: []*config.Domain{
+				{Template: config.Template{Host: "dyn.example.com", RefreshUrl: "<protocol>://<host>/update?user=<username>&pass=<password>&host=<domain>&ip=<ip4>"}, AuthUser: "jane", AuthPassword: "secret", DomainName: "home.example.org", Families: []string{"ip4"}},
+			},
+		},
+		{
+			name: "unsupported provider and options",
+			input: `fake-address = true
+provider cloudflare.com {
+    username = example.org
+    password = token
+    hostname = home.example.org
+    proxied = false
+}
+provider default@freedns.afraid.org {
+    username = jane
+    password = secret
+    hostname = free.example.org
+}`,
+			expectedDomains: []*config.Domain{
+				{Template: config.Template{RefreshUrl: ":freedns"}, AuthUser: "jane", AuthPassword: "secret", DomainName: "free.example.org", Families: []string{"ip4"}},
+			},
+			expectedProblems: []string{
+				`line 1: option "fake-address" is not supported and ignored`,
+				`line 6: option "proxied" is not supported and ignored`,
+				`line 2: "home.example.org": provider "cloudflare.com" is not supported, its api requires zone and record ids and a json request body, which a refresh url cannot express`,
+				`line 8: "free.example.org": password has to be replaced by the update token of the record`,
+			},
+		},
+		{
+			name:        "unclosed section",
+			input:       "provider default@dyndns.org {\n    hostname = home.example.org\n",
+			expectedErr: `line 1: provider "default@dyndns.org" is not closed`,
+		},
+		{
+			name:        "invalid option",
+			input:       "period 600",
+			expectedErr: `line 1: expected "period = <value>"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseInadyn(strings.NewReader(tt.input))
+
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedDomains, result.Domains)
+			assert.Equal(t, tt.expectedInterval, result.RefreshInterval)
+
+			var problems []string
+			for _, problem := range result.Problems {
+				problems = append(problems, problem.String())
+			}
+
+			assert.Equal(t, tt.expectedProblems, problems)
+		})
+	}
+}
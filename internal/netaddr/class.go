@@ -0,0 +1,62 @@
+package netaddr
+
+import (
+	"fmt"
+	"net/netip"
+)
+
+const (
+	ClassCgnat     = "cgnat"
+	ClassLinkLocal = "link-local"
+	ClassLoopback  = "loopback"
+	ClassPrivate   = "private"
+	ClassPublic    = "public"
+)
+
+var cgnatPrefix = netip.MustParsePrefix("100.64.0.0/10")
+
+var classDescriptions = map[string]string{
+	ClassCgnat:     "is in the carrier-grade nat range 100.64.0.0/10 - your provider shares one public IPv4 address between several customers, so it is not reachable from the internet (ask your provider for a public IPv4 address or refresh IPv6 only)",
+	ClassLinkLocal: "is a link-local address which is only valid on the local link",
+	ClassLoopback:  "is a loopback address",
+	ClassPrivate:   "is a private address (RFC 1918 / unique local) which is only reachable in the local network",
+}
+
+type ClassError struct {
+	Address string
+	Class   string
+}
+
+func (e *ClassError) Error() string {
+	return fmt.Sprintf("address %s %s", e.Address, classDescriptions[e.Class])
+}
+
+func ClassifyAddress(address string) (string, error) {
+	addr, err := netip.ParseAddr(address)
+	if err != nil {
+		return "", fmt.Errorf("invalid ip address \"%s\"", address)
+	}
+
+	addr = addr.Unmap()
+	switch {
+	case cgnatPrefix.Contains(addr):
+		return ClassCgnat, nil
+	case addr.IsLoopback():
+		return ClassLoopback, nil
+	case addr.IsLinkLocalUnicast():
+		return ClassLinkLocal, nil
+	case addr.IsPrivate():
+		return ClassPrivate, nil
+	}
+
+	return ClassPublic, nil
+}
+
+func CheckPublicAddress(address string) error {
+	class, err := ClassifyAddress(address)
+	if err != nil || class == ClassPublic {
+		return err
+	}
+
+	return &ClassError{Address: address, Class: class}
+}
@@ -0,0 +1,50 @@
+package netaddr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyAddress(t *testing.T) {
+	for _, test := range []struct {
+		address  string
+		expected string
+	}{
+		{address: "125.148.255.41", expected: ClassPublic},
+		{address: "100.64.0.1", expected: ClassCgnat},
+		{address: "100.127.255.254", expected: ClassCgnat},
+		{address: "100.128.0.1", expected: ClassPublic},
+		{address: "10.1.2.3", expected: ClassPrivate},
+		{address: "172.16.0.1", expected: ClassPrivate},
+		{address: "192.168.178.1", expected: ClassPrivate},
+		{address: "127.0.0.1", expected: ClassLoopback},
+		{address: "169.254.1.1", expected: ClassLinkLocal},
+		{address: "2001:db8::1", expected: ClassPublic},
+		{address: "fd00::1", expected: ClassPrivate},
+		{address: "fe80::1", expected: ClassLinkLocal},
+		{address: "::1", expected: ClassLoopback},
+		{address: "::ffff:100.64.0.1", expected: ClassCgnat},
+	} {
+		t.Run(test.address, func(t *testing.T) {
+			actual, err := ClassifyAddress(test.address)
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+
+	_, err := ClassifyAddress("invalid")
+	assert.EqualError(t, err, "invalid ip address \"invalid\"")
+}
+
+func TestCheckPublicAddress(t *testing.T) {
+	assert.NoError(t, CheckPublicAddress("125.148.255.41"))
+
+	err := CheckPublicAddress("100.64.0.1")
+
+	var classErr *ClassError
+	assert.ErrorAs(t, err, &classErr)
+	assert.Equal(t, ClassCgnat, classErr.Class)
+	assert.Contains(t, err.Error(), "address 100.64.0.1 is in the carrier-grade nat range 100.64.0.0/10")
+}
@@ -0,0 +1,158 @@
+package netaddr
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/netip"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	IfInet6FilePath = "/proc/net/if_inet6"
+
+	Ip6PreferEui64  = "eui64"
+	Ip6PreferSuffix = "suffix"
+
+	ScopeGlobal = 0x00
+
+	FlagTemporary  = 0x01
+	FlagDadFailed  = 0x08
+	FlagDeprecated = 0x20
+	FlagTentative  = 0x40
+	FlagPermanent  = 0x80
+)
+
+var SupportedIp6Preferences = []string{Ip6PreferEui64, Ip6PreferSuffix}
+
+type InterfaceAddressTable interface {
+	InterfaceAddresses() ([]InterfaceAddress, error)
+}
+
+type InterfaceAddress struct {
+	Address      netip.Addr
+	Device       string
+	Flags        int
+	PrefixLength int
+	Scope        int
+}
+
+func (a InterfaceAddress) IsStable() bool {
+	return a.Flags&(FlagTemporary|FlagDadFailed|FlagDeprecated|FlagTentative) == 0
+}
+
+func (a InterfaceAddress) IsEui64() bool {
+	address := a.Address.As16()
+
+	return address[11] == 0xff && address[12] == 0xfe
+}
+
+func (a InterfaceAddress) HasSuffix(suffix netip.Addr) bool {
+	address := a.Address.As16()
+	suffixBytes := suffix.As16()
+	for i := range address {
+		hostMask := byte(0xff)
+		if bits := a.PrefixLength - i*8; bits >= 8 {
+			hostMask = 0
+		} else if bits > 0 {
+			hostMask = byte(0xff >> bits)
+		}
+
+		if address[i]&hostMask != suffixBytes[i]&hostMask {
+			return false
+		}
+	}
+
+	return true
+}
+
+type ProcInterfaceAddressTable struct {
+	filePath string
+}
+
+func NewProcInterfaceAddressTable() *ProcInterfaceAddressTable {
+	return &ProcInterfaceAddressTable{filePath: IfInet6FilePath}
+}
+
+func (t *ProcInterfaceAddressTable) InterfaceAddresses() ([]InterfaceAddress, error) {
+	data, err := os.ReadFile(t.filePath)
+	if err != nil {
+		return nil, fmt.Errorf("could not read interface addresses: %w", err)
+	}
+
+	return ParseIfInet6(string(data))
+}
+
+func ParseIfInet6(content string) ([]InterfaceAddress, error) {
+	var addresses []InterfaceAddress
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		if len(fields) != 6 || len(fields[0]) != 32 {
+			return nil, fmt.Errorf("invalid if_inet6 line \"%s\"", line)
+		}
+
+		addressBytes, err := hex.DecodeString(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid if_inet6 address \"%s\"", fields[0])
+		}
+
+		var values [3]int64
+		for i, field := range fields[2:5] {
+			values[i], err = strconv.ParseInt(field, 16, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid if_inet6 line \"%s\"", line)
+			}
+		}
+
+		addresses = append(addresses, InterfaceAddress{
+			Address:      netip.AddrFrom16([16]byte(addressBytes)),
+			Device:       fields[5],
+			Flags:        int(values[2]),
+			PrefixLength: int(values[0]),
+			Scope:        int(values[1]),
+		})
+	}
+
+	return addresses, nil
+}
+
+func SelectIp6InterfaceAddress(addresses []InterfaceAddress, device string, prefer string, suffix string) (string, error) {
+	var candidates []InterfaceAddress
+	for _, address := range addresses {
+		if address.Device == device && address.Scope == ScopeGlobal && address.Address.IsGlobalUnicast() && address.IsStable() {
+			candidates = append(candidates, address)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("no stable global ipv6 address found on interface \"%s\"", device)
+	}
+
+	switch prefer {
+	case Ip6PreferEui64:
+		for _, candidate := range candidates {
+			if candidate.IsEui64() {
+				return candidate.Address.String(), nil
+			}
+		}
+
+	case Ip6PreferSuffix:
+		suffixAddr, err := ParseIp6HostId(suffix)
+		if err != nil {
+			return "", err
+		}
+
+		for _, candidate := range candidates {
+			if candidate.HasSuffix(suffixAddr) {
+				return candidate.Address.String(), nil
+			}
+		}
+	}
+
+	return candidates[0].Address.String(), nil
+}
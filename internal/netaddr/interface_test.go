@@ -0,0 +1,58 @@
+package netaddr
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const ifInet6Content = `fe800000000000000211223344556677 02 40 20 80     eth0
+20010db8000000010000000000001234 02 40 00 80     eth0
+20010db80000000112345678abcdef01 02 40 00 01     eth0
+20010db800000001021122fffe334455 02 40 00 00     eth0
+20010db80000000100000000000000aa 02 40 00 20     eth0
+20010db80000000100000000000000bb 02 40 00 40     eth0
+20010db8000000020000000000000001 03 40 00 80     wlan0
+00000000000000000000000000000001 01 80 10 80        lo
+`
+
+func TestParseIfInet6(t *testing.T) {
+	addresses, err := ParseIfInet6(ifInet6Content)
+
+	assert.NoError(t, err)
+	assert.Len(t, addresses, 8)
+	assert.Equal(t, InterfaceAddress{Address: netip.MustParseAddr("2001:db8:0:1::1234"), Device: "eth0", Flags: 0x80, PrefixLength: 64, Scope: 0}, addresses[1])
+	assert.Equal(t, InterfaceAddress{Address: netip.MustParseAddr("::1"), Device: "lo", Flags: 0x80, PrefixLength: 128, Scope: 0x10}, addresses[7])
+
+	_, err = ParseIfInet6("invalid")
+	assert.EqualError(t, err, "invalid if_inet6 line \"invalid\"")
+}
+
+func TestSelectIp6InterfaceAddress(t *testing.T) {
+	addresses, _ := ParseIfInet6(ifInet6Content)
+
+	for _, test := range []struct {
+		name     string
+		device   string
+		prefer   string
+		suffix   string
+		expected string
+	}{
+		{name: "First stable address", device: "eth0", expected: "2001:db8:0:1::1234"},
+		{name: "Prefer EUI-64", device: "eth0", prefer: Ip6PreferEui64, expected: "2001:db8:0:1:211:22ff:fe33:4455"},
+		{name: "Prefer suffix", device: "eth0", prefer: Ip6PreferSuffix, suffix: "211:22ff:fe33:4455", expected: "2001:db8:0:1:211:22ff:fe33:4455"},
+		{name: "Temporary address is never preferred", device: "eth0", prefer: Ip6PreferSuffix, suffix: "::1234:5678:abcd:ef01", expected: "2001:db8:0:1::1234"},
+		{name: "Other interface", device: "wlan0", prefer: Ip6PreferEui64, expected: "2001:db8:0:2::1"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := SelectIp6InterfaceAddress(addresses, test.device, test.prefer, test.suffix)
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+
+	_, err := SelectIp6InterfaceAddress(addresses, "lo", "", "")
+	assert.EqualError(t, err, "no stable global ipv6 address found on interface \"lo\"")
+}
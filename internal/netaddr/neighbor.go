@@ -0,0 +1,114 @@
+package netaddr
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"os/exec"
+	"strings"
+)
+
+const (
+	NeighborStateFailed     = "FAILED"
+	NeighborStateIncomplete = "INCOMPLETE"
+)
+
+type NeighborTable interface {
+	Neighbors() ([]Neighbor, error)
+}
+
+type Neighbor struct {
+	Address netip.Addr
+	Device  string
+	Mac     string
+	State   string
+}
+
+type IpNeighborTable struct {
+	command string
+}
+
+func NewIpNeighborTable() *IpNeighborTable {
+	return &IpNeighborTable{command: "ip"}
+}
+
+func (t *IpNeighborTable) Neighbors() ([]Neighbor, error) {
+	output, err := exec.Command(t.command, "-6", "neigh", "show").Output()
+	if err != nil {
+		return nil, fmt.Errorf("could not read neighbor table: %w", err)
+	}
+
+	return ParseIpNeighOutput(string(output)), nil
+}
+
+func ParseIpNeighOutput(output string) []Neighbor {
+	var neighbors []Neighbor
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		address, err := netip.ParseAddr(fields[0])
+		if err != nil {
+			continue
+		}
+
+		neighbor := Neighbor{Address: address}
+		for i := 1; i < len(fields); i++ {
+			switch {
+			case fields[i] == "dev" && i+1 < len(fields):
+				i++
+				neighbor.Device = fields[i]
+			case fields[i] == "lladdr" && i+1 < len(fields):
+				i++
+				neighbor.Mac = strings.ToLower(fields[i])
+			case fields[i] == strings.ToUpper(fields[i]):
+				neighbor.State = fields[i]
+			}
+		}
+
+		neighbors = append(neighbors, neighbor)
+	}
+
+	return neighbors
+}
+
+func FindGlobalNeighborAddresses(neighbors []Neighbor, mac string) ([]netip.Addr, error) {
+	hardwareAddr, err := net.ParseMAC(mac)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mac address \"%s\"", mac)
+	}
+
+	var addresses []netip.Addr
+	for _, neighbor := range neighbors {
+		if neighbor.Mac != hardwareAddr.String() || neighbor.State == NeighborStateFailed || neighbor.State == NeighborStateIncomplete {
+			continue
+		}
+
+		if neighbor.Address.Is6() && neighbor.Address.IsGlobalUnicast() && !neighbor.Address.IsPrivate() {
+			addresses = append(addresses, neighbor.Address)
+		}
+	}
+
+	return addresses, nil
+}
+
+func CreateEui64HostId(mac string) (string, error) {
+	hardwareAddr, err := net.ParseMAC(mac)
+	if err != nil || len(hardwareAddr) != 6 {
+		return "", fmt.Errorf("invalid mac address \"%s\"", mac)
+	}
+
+	var hostId [16]byte
+	hostId[8] = hardwareAddr[0] ^ 0x02
+	hostId[9] = hardwareAddr[1]
+	hostId[10] = hardwareAddr[2]
+	hostId[11] = 0xff
+	hostId[12] = 0xfe
+	hostId[13] = hardwareAddr[3]
+	hostId[14] = hardwareAddr[4]
+	hostId[15] = hardwareAddr[5]
+
+	return netip.AddrFrom16(hostId).String(), nil
+}
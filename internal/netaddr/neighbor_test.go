@@ -0,0 +1,53 @@
+package netaddr
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateEui64HostId(t *testing.T) {
+	hostId, err := CreateEui64HostId("00:11:22:33:44:55")
+	assert.NoError(t, err)
+	assert.Equal(t, "::211:22ff:fe33:4455", hostId)
+
+	hostId, err = CreateEui64HostId("02-AB-CD-EF-01-23")
+	assert.NoError(t, err)
+	assert.Equal(t, "::ab:cdff:feef:123", hostId)
+
+	_, err = CreateEui64HostId("00:11:22")
+	assert.EqualError(t, err, "invalid mac address \"00:11:22\"")
+}
+
+func TestParseIpNeighOutput(t *testing.T) {
+	output := `2001:db8::5 dev br-lan lladdr 00:11:22:33:44:55 REACHABLE
+fe80::211:22ff:fe33:4455 dev br-lan lladdr 00:11:22:33:44:55 router STALE
+2001:db8::9 dev br-lan  FAILED
+
+invalid line
+`
+
+	neighbors := ParseIpNeighOutput(output)
+
+	assert.Equal(t, []Neighbor{
+		{Address: netip.MustParseAddr("2001:db8::5"), Device: "br-lan", Mac: "00:11:22:33:44:55", State: "REACHABLE"},
+		{Address: netip.MustParseAddr("fe80::211:22ff:fe33:4455"), Device: "br-lan", Mac: "00:11:22:33:44:55", State: "STALE"},
+		{Address: netip.MustParseAddr("2001:db8::9"), Device: "br-lan", State: "FAILED"},
+	}, neighbors)
+}
+
+func TestFindGlobalNeighborAddresses(t *testing.T) {
+	neighbors := []Neighbor{
+		{Address: netip.MustParseAddr("2001:db8::5"), Mac: "00:11:22:33:44:55", State: "REACHABLE"},
+		{Address: netip.MustParseAddr("fe80::211:22ff:fe33:4455"), Mac: "00:11:22:33:44:55", State: "STALE"},
+		{Address: netip.MustParseAddr("fd00::5"), Mac: "00:11:22:33:44:55", State: "STALE"},
+		{Address: netip.MustParseAddr("2001:db8::6"), Mac: "00:11:22:33:44:55", State: "FAILED"},
+		{Address: netip.MustParseAddr("2001:db8::7"), Mac: "00:11:22:33:44:66", State: "REACHABLE"},
+	}
+
+	addresses, err := FindGlobalNeighborAddresses(neighbors, "00:11:22:33:44:55")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []netip.Addr{netip.MustParseAddr("2001:db8::5")}, addresses)
+}
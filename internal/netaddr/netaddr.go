@@ -0,0 +1,83 @@
+package netaddr
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+const Ip6PrefixLengthDefault = 64
+
+func ComposeIp6(prefix string, prefixLength int, hostId string) (string, error) {
+	prefixAddr, prefixLength, err := ParseIp6Prefix(prefix, prefixLength)
+	if err != nil {
+		return "", err
+	}
+
+	hostIdAddr, err := ParseIp6HostId(hostId)
+	if err != nil {
+		return "", err
+	}
+
+	prefixBytes := prefixAddr.As16()
+	hostIdBytes := hostIdAddr.As16()
+
+	var composed [16]byte
+	for i := range composed {
+		prefixMask := byte(0)
+		if bits := prefixLength - i*8; bits >= 8 {
+			prefixMask = 0xff
+		} else if bits > 0 {
+			prefixMask = byte(0xff << (8 - bits))
+		}
+
+		composed[i] = prefixBytes[i]&prefixMask | hostIdBytes[i]&^prefixMask
+	}
+
+	composedAddr := netip.AddrFrom16(composed)
+	if !composedAddr.IsGlobalUnicast() {
+		return "", fmt.Errorf("composed address \"%s\" is not a global unicast address", composedAddr)
+	}
+
+	return composedAddr.String(), nil
+}
+
+func ParseIp6Prefix(prefix string, prefixLength int) (netip.Addr, int, error) {
+	if strings.Contains(prefix, "/") {
+		parsedPrefix, err := netip.ParsePrefix(prefix)
+		if err != nil {
+			return netip.Addr{}, 0, fmt.Errorf("invalid ipv6 prefix \"%s\": %w", prefix, err)
+		}
+
+		prefix = parsedPrefix.Addr().String()
+		prefixLength = parsedPrefix.Bits()
+	}
+
+	if prefixLength <= 0 || prefixLength > 128 {
+		return netip.Addr{}, 0, fmt.Errorf("invalid ipv6 prefix length %d", prefixLength)
+	}
+
+	addr, err := netip.ParseAddr(prefix)
+	if err != nil {
+		return netip.Addr{}, 0, fmt.Errorf("invalid ipv6 prefix \"%s\": %w", prefix, err)
+	}
+
+	if !addr.Is6() || addr.Is4In6() {
+		return netip.Addr{}, 0, fmt.Errorf("prefix \"%s\" is not an ipv6 address", prefix)
+	}
+
+	return addr.WithZone(""), prefixLength, nil
+}
+
+func ParseIp6HostId(hostId string) (netip.Addr, error) {
+	addr, err := netip.ParseAddr(hostId)
+	if err != nil {
+		addr, err = netip.ParseAddr("::" + hostId)
+	}
+
+	if err != nil || !addr.Is6() || addr.Is4In6() {
+		return netip.Addr{}, fmt.Errorf("invalid ipv6 host id \"%s\"", hostId)
+	}
+
+	return addr, nil
+}
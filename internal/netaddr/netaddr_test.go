@@ -0,0 +1,110 @@
+package netaddr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComposeIp6(t *testing.T) {
+	for _, test := range composeIp6Table() {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := ComposeIp6(test.prefix, test.prefixLength, test.hostId)
+			if test.expectedError != "" {
+				assert.EqualError(t, err, test.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expected, actual)
+			}
+		})
+	}
+}
+
+func composeIp6Table() []struct {
+	name          string
+	prefix        string
+	prefixLength  int
+	hostId        string
+	expected      string
+	expectedError string
+} {
+	return []struct {
+		name          string
+		prefix        string
+		prefixLength  int
+		hostId        string
+		expected      string
+		expectedError string
+	}{
+		{
+			name:         "Full address with interface id form",
+			prefix:       "d710:6c3b:b3c3:9f6b:1:2:3:4",
+			prefixLength: 64,
+			hostId:       "a7cc:409a:e841:ea15",
+			expected:     "d710:6c3b:b3c3:9f6b:a7cc:409a:e841:ea15",
+		},
+		{
+			name:         "Compressed address with compressed host id",
+			prefix:       "2001:db8::1",
+			prefixLength: 64,
+			hostId:       "::1234",
+			expected:     "2001:db8::1234",
+		},
+		{
+			name:         "Full host id form",
+			prefix:       "2001:db8:0:5::1",
+			prefixLength: 64,
+			hostId:       "0:0:0:0:5b7a:f9bf:d5b7:a9a8",
+			expected:     "2001:db8:0:5:5b7a:f9bf:d5b7:a9a8",
+		},
+		{
+			name:         "Prefix length 56 takes subnet bits from host id",
+			prefix:       "2001:db8:aa:bbcc::1",
+			prefixLength: 56,
+			hostId:       "0:0:0:ff::5",
+			expected:     "2001:db8:aa:bbff::5",
+		},
+		{
+			name:         "Prefix length 60",
+			prefix:       "2001:db8:aa:bbcc::1",
+			prefixLength: 60,
+			hostId:       "0:0:0:f::5",
+			expected:     "2001:db8:aa:bbcf::5",
+		},
+		{
+			name:         "CIDR notation overrides prefix length",
+			prefix:       "2001:db8:aa:bb00::/56",
+			prefixLength: 64,
+			hostId:       "0:0:0:12::1",
+			expected:     "2001:db8:aa:bb12::1",
+		},
+		{
+			name:          "Link local prefix is not global",
+			prefix:        "fe80::1",
+			prefixLength:  64,
+			hostId:        "::1",
+			expectedError: "composed address \"fe80::1\" is not a global unicast address",
+		},
+		{
+			name:          "IPv4 prefix",
+			prefix:        "192.168.1.1",
+			prefixLength:  64,
+			hostId:        "::1",
+			expectedError: "prefix \"192.168.1.1\" is not an ipv6 address",
+		},
+		{
+			name:          "Invalid host id",
+			prefix:        "2001:db8::1",
+			prefixLength:  64,
+			hostId:        "xyz",
+			expectedError: "invalid ipv6 host id \"xyz\"",
+		},
+		{
+			name:          "Invalid prefix length",
+			prefix:        "2001:db8::1",
+			prefixLength:  129,
+			hostId:        "::1",
+			expectedError: "invalid ipv6 prefix length 129",
+		},
+	}
+}
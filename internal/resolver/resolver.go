@@ -0,0 +1,128 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultPort           = "53"
+	DefaultTimeoutSeconds = 5
+
+	ServerAuthoritative = "authoritative"
+	ServerSystem        = ""
+)
+
+type Resolver interface {
+	Lookup(ctx context.Context, server string, domain string, family string) ([]string, error)
+}
+
+type DnsResolver struct {
+	timeout time.Duration
+}
+
+func NewDnsResolverWithDefaultValues() *DnsResolver {
+	return NewDnsResolver(DefaultTimeoutSeconds)
+}
+
+func NewDnsResolver(timeoutSeconds int) *DnsResolver {
+	return &DnsResolver{timeout: time.Duration(timeoutSeconds) * time.Second}
+}
+
+func (r *DnsResolver) Lookup(ctx context.Context, server string, domain string, family string) ([]string, error) {
+	if server != ServerAuthoritative {
+		return r.lookup(ctx, server, domain, family)
+	}
+
+	servers, err := r.FindAuthoritativeServers(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	for _, server = range servers {
+		addresses, err := r.lookup(ctx, server, domain, family)
+		if err == nil {
+			return addresses, nil
+		}
+
+		errs = append(errs, err)
+	}
+
+	return nil, errors.Join(errs...)
+}
+
+func (r *DnsResolver) FindAuthoritativeServers(ctx context.Context, domain string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	name := strings.TrimSuffix(domain, ".")
+	for strings.Contains(name, ".") {
+		nameServers, err := net.DefaultResolver.LookupNS(ctx, createFqdn(name))
+		if err == nil && len(nameServers) > 0 {
+			var servers []string
+			for _, nameServer := range nameServers {
+				servers = append(servers, nameServer.Host)
+			}
+
+			return servers, nil
+		}
+
+		_, name, _ = strings.Cut(name, ".")
+	}
+
+	return nil, fmt.Errorf("no authoritative name servers found for \"%s\"", domain)
+}
+
+func (r *DnsResolver) lookup(ctx context.Context, server string, domain string, family string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	ips, err := r.createNetResolver(server).LookupIP(ctx, family, createFqdn(domain))
+	if err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return []string{}, nil
+		}
+
+		return nil, err
+	}
+
+	addresses := []string{}
+	for _, ip := range ips {
+		addresses = append(addresses, ip.String())
+	}
+
+	return addresses, nil
+}
+
+func (r *DnsResolver) createNetResolver(server string) *net.Resolver {
+	if server == ServerSystem {
+		return net.DefaultResolver
+	}
+
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, DefaultPort)
+	}
+
+	dialer := net.Dialer{Timeout: r.timeout}
+
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network string, _ string) (net.Conn, error) {
+			return dialer.DialContext(ctx, network, server)
+		},
+	}
+}
+
+func createFqdn(domain string) string {
+	if strings.HasSuffix(domain, ".") {
+		return domain
+	}
+
+	return domain + "."
+}
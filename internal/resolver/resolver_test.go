@@ -0,0 +1,100 @@
+package resolver
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+func TestDnsResolver_Lookup(t *testing.T) {
+	server := startDnsServer(t, map[string][]string{
+		"yddns.drieschel.test.": {"125.148.255.41", "2001:db8::1"},
+	})
+
+	r := NewDnsResolver(2)
+
+	addresses, err := r.Lookup(context.Background(), server, "yddns.drieschel.test", "ip4")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"125.148.255.41"}, addresses)
+
+	addresses, err = r.Lookup(context.Background(), server, "yddns.drieschel.test", "ip6")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2001:db8::1"}, addresses)
+
+	addresses, err = r.Lookup(context.Background(), server, "unknown.drieschel.test", "ip4")
+	assert.NoError(t, err)
+	assert.Empty(t, addresses)
+}
+
+func TestCreateFqdn(t *testing.T) {
+	assert.Equal(t, "foo.tld.", createFqdn("foo.tld"))
+	assert.Equal(t, "foo.tld.", createFqdn("foo.tld."))
+}
+
+func startDnsServer(t *testing.T, records map[string][]string) string {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+
+			response, err := createDnsResponse(buf[:n], records)
+			if err == nil {
+				_, _ = conn.WriteTo(response, addr)
+			}
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+func createDnsResponse(request []byte, records map[string][]string) ([]byte, error) {
+	var parser dnsmessage.Parser
+	header, err := parser.Start(request)
+	if err != nil {
+		return nil, err
+	}
+
+	question, err := parser.Question()
+	if err != nil {
+		return nil, err
+	}
+
+	addresses, found := records[question.Name.String()]
+
+	rcode := dnsmessage.RCodeSuccess
+	if !found {
+		rcode = dnsmessage.RCodeNameError
+	}
+
+	builder := dnsmessage.NewBuilder(nil, dnsmessage.Header{ID: header.ID, Response: true, Authoritative: true, RCode: rcode})
+	builder.EnableCompression()
+	_ = builder.StartQuestions()
+	_ = builder.Question(question)
+	_ = builder.StartAnswers()
+
+	resourceHeader := dnsmessage.ResourceHeader{Name: question.Name, Type: question.Type, Class: dnsmessage.ClassINET, TTL: 60}
+	for _, address := range addresses {
+		ip := netip.MustParseAddr(address)
+		if question.Type == dnsmessage.TypeA && ip.Is4() {
+			_ = builder.AResource(resourceHeader, dnsmessage.AResource{A: ip.As4()})
+		} else if question.Type == dnsmessage.TypeAAAA && ip.Is6() {
+			_ = builder.AAAAResource(resourceHeader, dnsmessage.AAAAResource{AAAA: ip.As16()})
+		}
+	}
+
+	return builder.Finish()
+}
@@ -0,0 +1,81 @@
+package systemd
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	EnvNotifySocket = "NOTIFY_SOCKET"
+	EnvWatchdogPid  = "WATCHDOG_PID"
+	EnvWatchdogUsec = "WATCHDOG_USEC"
+
+	StateReady    = "READY=1"
+	StateStopping = "STOPPING=1"
+	StateWatchdog = "WATCHDOG=1"
+)
+
+type Notifier interface {
+	Notify(states ...string) error
+}
+
+type SocketNotifier struct {
+	socket string
+}
+
+func NewSocketNotifier(socket string) *SocketNotifier {
+	return &SocketNotifier{socket: socket}
+}
+
+func NewSocketNotifierWithDefaultValues() *SocketNotifier {
+	return NewSocketNotifier(os.Getenv(EnvNotifySocket))
+}
+
+func (n *SocketNotifier) Notify(states ...string) error {
+	if n.socket == "" || len(states) == 0 {
+		return nil
+	}
+
+	name := n.socket
+	if strings.HasPrefix(name, "@") {
+		name = "\x00" + name[1:]
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: name, Net: "unixgram"})
+	if err != nil {
+		return err
+	}
+
+	defer conn.Close()
+
+	_, err = conn.Write([]byte(strings.Join(states, "\n")))
+
+	return err
+}
+
+func Status(status string) string {
+	return "STATUS=" + status
+}
+
+func WatchdogInterval() (time.Duration, error) {
+	usec := os.Getenv(EnvWatchdogUsec)
+	if usec == "" {
+		return 0, nil
+	}
+
+	pid := os.Getenv(EnvWatchdogPid)
+	if pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0, nil
+	}
+
+	value, err := strconv.ParseInt(usec, 10, 64)
+	if err != nil || value <= 0 {
+		return 0, fmt.Errorf("%s \"%s\" is invalid", EnvWatchdogUsec, usec)
+	}
+
+	return time.Duration(value) * time.Microsecond, nil
+}
@@ -0,0 +1,86 @@
+package systemd
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSocketNotifier_Notify(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "notify.sock")
+	conn := listenUnixgram(t, socket)
+
+	err := NewSocketNotifier(socket).Notify(StateReady, Status("2 domains refreshed"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "READY=1\nSTATUS=2 domains refreshed", readDatagram(t, conn))
+}
+
+func TestSocketNotifier_NotifyWithoutSocket(t *testing.T) {
+	err := NewSocketNotifier("").Notify(StateReady)
+
+	assert.NoError(t, err)
+}
+
+func TestSocketNotifier_NotifyWithMissingSocket(t *testing.T) {
+	err := NewSocketNotifier(filepath.Join(t.TempDir(), "missing.sock")).Notify(StateReady)
+
+	assert.Error(t, err)
+}
+
+func TestWatchdogInterval(t *testing.T) {
+	tests := []struct {
+		name        string
+		usec        string
+		pid         string
+		expected    time.Duration
+		expectedErr string
+	}{
+		{name: "disabled"},
+		{name: "enabled", usec: "30000000", expected: 30 * time.Second},
+		{name: "own pid", usec: "30000000", pid: strconv.Itoa(os.Getpid()), expected: 30 * time.Second},
+		{name: "other pid", usec: "30000000", pid: "1"},
+		{name: "invalid", usec: "abc", expectedErr: "WATCHDOG_USEC \"abc\" is invalid"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv(EnvWatchdogUsec, test.usec)
+			t.Setenv(EnvWatchdogPid, test.pid)
+
+			actual, err := WatchdogInterval()
+
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func listenUnixgram(t *testing.T, socket string) *net.UnixConn {
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+func readDatagram(t *testing.T, conn *net.UnixConn) string {
+	buffer := make([]byte, 1024)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+
+	n, err := conn.Read(buffer)
+	require.NoError(t, err)
+
+	return string(buffer[:n])
+}
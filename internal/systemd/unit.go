@@ -0,0 +1,173 @@
+package systemd
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+const (
+	ModeService = "service"
+	ModeTimer   = "timer"
+
+	UnitName = "yddns"
+
+	WatchdogSecDefault = 300
+)
+
+var SupportedModes = []string{ModeService, ModeTimer}
+
+var serviceTemplate = template.Must(template.New("service").Parse(`# generated by "yddns systemd generate"
+[Unit]
+Description=Refresh dynamic dns domains (yddns)
+Documentation=https://github.com/drieschel/yddns
+{{- if not .User }}
+Wants=network-online.target
+After=network-online.target
+{{- end }}
+
+[Service]
+{{- if eq .Mode "service" }}
+Type=notify
+ExecStart={{ .Executable }} refresh --periodically{{ .ConfigArgs }}
+Restart=on-failure
+RestartSec=30
+{{- if .WatchdogSec }}
+WatchdogSec={{ .WatchdogSec }}
+{{- end }}
+{{- else }}
+Type=oneshot
+ExecStart={{ .Executable }} refresh{{ .ConfigArgs }}
+{{- end }}
+NoNewPrivileges=yes
+{{- if not .User }}
+Environment=XDG_CACHE_HOME=/var/cache XDG_STATE_HOME=/var/lib
+CacheDirectory=yddns
+StateDirectory=yddns
+CapabilityBoundingSet=
+AmbientCapabilities=
+LockPersonality=yes
+MemoryDenyWriteExecute=yes
+PrivateDevices=yes
+PrivateTmp=yes
+ProtectClock=yes
+ProtectControlGroups=yes
+ProtectHome=read-only
+ProtectHostname=yes
+ProtectKernelLogs=yes
+ProtectKernelModules=yes
+ProtectKernelTunables=yes
+ProtectProc=invisible
+ProtectSystem=strict
+RestrictAddressFamilies=AF_UNIX AF_INET AF_INET6 AF_NETLINK
+RestrictNamespaces=yes
+RestrictRealtime=yes
+RestrictSUIDSGID=yes
+SystemCallArchitectures=native
+SystemCallErrorNumber=EPERM
+SystemCallFilter=@system-service
+UMask=0077
+{{- end }}
+{{- if eq .Mode "service" }}
+
+[Install]
+WantedBy={{ .WantedBy }}
+{{- end }}
+`))
+
+var timerTemplate = template.Must(template.New("timer").Parse(`# generated by "yddns systemd generate"
+[Unit]
+Description=Refresh dynamic dns domains periodically (yddns)
+Documentation=https://github.com/drieschel/yddns
+
+[Timer]
+OnBootSec=1min
+OnUnitActiveSec={{ .RefreshInterval }}s
+AccuracySec=1s
+
+[Install]
+WantedBy=timers.target
+`))
+
+type UnitOptions struct {
+	ConfigFile      string
+	Executable      string
+	Mode            string
+	RefreshInterval int
+	User            bool
+	WatchdogSec     int
+}
+
+type Unit struct {
+	Name    string
+	Content string
+}
+
+func GenerateUnits(options *UnitOptions) ([]*Unit, error) {
+	if !slices.Contains(SupportedModes, options.Mode) {
+		return nil, fmt.Errorf("mode \"%s\" is not supported [%s]", options.Mode, strings.Join(SupportedModes, "|"))
+	}
+
+	if options.Mode == ModeTimer && options.RefreshInterval <= 0 {
+		return nil, fmt.Errorf("refresh interval %d is invalid", options.RefreshInterval)
+	}
+
+	if options.Mode == ModeService && options.WatchdogSec < 0 {
+		return nil, fmt.Errorf("watchdog sec %d is invalid", options.WatchdogSec)
+	}
+
+	configArgs := ""
+	if options.ConfigFile != "" {
+		configArgs = " --config-file " + quoteArg(options.ConfigFile)
+	}
+
+	wantedBy := "multi-user.target"
+	if options.User {
+		wantedBy = "default.target"
+	}
+
+	data := map[string]any{
+		"ConfigArgs":      configArgs,
+		"Executable":      quoteArg(options.Executable),
+		"Mode":            options.Mode,
+		"RefreshInterval": options.RefreshInterval,
+		"User":            options.User,
+		"WantedBy":        wantedBy,
+		"WatchdogSec":     options.WatchdogSec,
+	}
+
+	service, err := renderUnit(serviceTemplate, data)
+	if err != nil {
+		return nil, err
+	}
+
+	units := []*Unit{{Name: UnitName + ".service", Content: service}}
+	if options.Mode == ModeTimer {
+		timer, err := renderUnit(timerTemplate, data)
+		if err != nil {
+			return nil, err
+		}
+
+		units = append(units, &Unit{Name: UnitName + ".timer", Content: timer})
+	}
+
+	return units, nil
+}
+
+func renderUnit(tmpl *template.Template, data map[string]any) (string, error) {
+	var buffer bytes.Buffer
+	err := tmpl.Execute(&buffer, data)
+
+	return buffer.String(), err
+}
+
+func quoteArg(arg string) string {
+	if arg == "" || strings.ContainsAny(arg, " \t\"'\\%$;") {
+		return strings.NewReplacer("%", "%%", "$", "$$").Replace(strconv.Quote(arg))
+	}
+
+	return arg
+}
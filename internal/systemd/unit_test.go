@@ -0,0 +1,103 @@
+package systemd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateUnits(t *testing.T) {
+	tests := []struct {
+		name             string
+		options          UnitOptions
+		expectedNames    []string
+		expectedContains []string
+		expectedMissing  []string
+		expectedErr      string
+	}{
+		{
+			name:             "service",
+			options:          UnitOptions{Executable: "/usr/bin/yddns", Mode: ModeService, RefreshInterval: 300, WatchdogSec: WatchdogSecDefault},
+			expectedNames:    []string{"yddns.service"},
+			expectedContains: []string{"Type=notify", "ExecStart=/usr/bin/yddns refresh --periodically", "WatchdogSec=300", "ProtectSystem=strict", "StateDirectory=yddns", "WantedBy=multi-user.target"},
+			expectedMissing:  []string{"Type=oneshot"},
+		},
+		{
+			name:             "timer",
+			options:          UnitOptions{Executable: "/usr/bin/yddns", ConfigFile: "/etc/yddns/config.toml", Mode: ModeTimer, RefreshInterval: 300},
+			expectedNames:    []string{"yddns.service", "yddns.timer"},
+			expectedContains: []string{"Type=oneshot", "ExecStart=/usr/bin/yddns refresh --config-file /etc/yddns/config.toml", "OnUnitActiveSec=300s", "WantedBy=timers.target"},
+			expectedMissing:  []string{"WatchdogSec", "WantedBy=multi-user.target"},
+		},
+		{
+			name:             "user service",
+			options:          UnitOptions{Executable: "/home/jane/go/bin/yddns", ConfigFile: "/home/jane/my config.toml", Mode: ModeService, User: true},
+			expectedNames:    []string{"yddns.service"},
+			expectedContains: []string{"ExecStart=/home/jane/go/bin/yddns refresh --periodically --config-file \"/home/jane/my config.toml\"", "NoNewPrivileges=yes", "WantedBy=default.target"},
+			expectedMissing:  []string{"network-online.target", "ProtectSystem", "CacheDirectory"},
+		},
+		{
+			name:             "service with watchdog sec",
+			options:          UnitOptions{Executable: "/usr/bin/yddns", Mode: ModeService, WatchdogSec: 900},
+			expectedNames:    []string{"yddns.service"},
+			expectedContains: []string{"WatchdogSec=900"},
+		},
+		{
+			name:             "service without watchdog",
+			options:          UnitOptions{Executable: "/usr/bin/yddns", Mode: ModeService},
+			expectedNames:    []string{"yddns.service"},
+			expectedContains: []string{"Type=notify", "RestartSec=30\nNoNewPrivileges=yes"},
+			expectedMissing:  []string{"WatchdogSec"},
+		},
+		{
+			name:        "invalid watchdog sec",
+			options:     UnitOptions{Executable: "/usr/bin/yddns", Mode: ModeService, WatchdogSec: -1},
+			expectedErr: "watchdog sec -1 is invalid",
+		},
+		{
+			name:        "unsupported mode",
+			options:     UnitOptions{Executable: "/usr/bin/yddns", Mode: "cron"},
+			expectedErr: "mode \"cron\" is not supported [service|timer]",
+		},
+		{
+			name:        "timer without interval",
+			options:     UnitOptions{Executable: "/usr/bin/yddns", Mode: ModeTimer},
+			expectedErr: "refresh interval 0 is invalid",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			units, err := GenerateUnits(&test.options)
+
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+
+			var names []string
+			content := ""
+			for _, unit := range units {
+				names = append(names, unit.Name)
+				content += unit.Content
+			}
+
+			assert.Equal(t, test.expectedNames, names)
+			for _, expected := range test.expectedContains {
+				assert.Contains(t, content, expected)
+			}
+
+			for _, missing := range test.expectedMissing {
+				assert.NotContains(t, content, missing)
+			}
+		})
+	}
+}
+
+func TestQuoteArg(t *testing.T) {
+	assert.Equal(t, "/usr/bin/yddns", quoteArg("/usr/bin/yddns"))
+	assert.Equal(t, "\"/opt/my yddns\"", quoteArg("/opt/my yddns"))
+	assert.Equal(t, "\"/opt/100%%/$$HOME\"", quoteArg("/opt/100%/$HOME"))
+}
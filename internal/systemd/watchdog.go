@@ -0,0 +1,65 @@
+package systemd
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type Watchdog struct {
+	busy     bool
+	interval time.Duration
+	mutex    sync.Mutex
+	notifier Notifier
+}
+
+func NewWatchdog(notifier Notifier, interval time.Duration) *Watchdog {
+	return &Watchdog{notifier: notifier, interval: interval}
+}
+
+func (w *Watchdog) Ping() error {
+	if w.interval <= 0 {
+		return nil
+	}
+
+	return w.notifier.Notify(StateWatchdog)
+}
+
+func (w *Watchdog) SetBusy(busy bool) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.busy = busy
+}
+
+func (w *Watchdog) IsBusy() bool {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return w.busy
+}
+
+func (w *Watchdog) Run(ctx context.Context, errorHandler func(err error)) {
+	if w.interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(w.interval / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if w.IsBusy() {
+				continue
+			}
+
+			err := w.Ping()
+			if err != nil {
+				errorHandler(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package systemd
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatchdog_Ping(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "notify.sock")
+	conn := listenUnixgram(t, socket)
+
+	err := NewWatchdog(NewSocketNotifier(socket), time.Minute).Ping()
+
+	assert.NoError(t, err)
+	assert.Equal(t, StateWatchdog, readDatagram(t, conn))
+}
+
+func TestWatchdog_PingDisabled(t *testing.T) {
+	err := NewWatchdog(NewSocketNotifier(filepath.Join(t.TempDir(), "missing.sock")), 0).Ping()
+
+	assert.NoError(t, err)
+}
+
+func TestWatchdog_Run(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "notify.sock")
+	conn := listenUnixgram(t, socket)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	watchdog := NewWatchdog(NewSocketNotifier(socket), 20*time.Millisecond)
+	done := make(chan struct{})
+	go func() {
+		watchdog.Run(ctx, func(err error) { assert.NoError(t, err) })
+		close(done)
+	}()
+
+	assert.Equal(t, StateWatchdog, readDatagram(t, conn))
+
+	cancel()
+	<-done
+}
+
+func TestWatchdog_RunContinuesAfterNotifyError(t *testing.T) {
+	notifier := &failingNotifier{failures: 2}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	var errs []error
+	NewWatchdog(notifier, 20*time.Millisecond).Run(ctx, func(err error) {
+		errs = append(errs, err)
+	})
+
+	assert.Len(t, errs, 2)
+	assert.Greater(t, notifier.calls, 2)
+}
+
+func TestWatchdog_RunWhileBusy(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "notify.sock")
+	conn := listenUnixgram(t, socket)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	watchdog := NewWatchdog(NewSocketNotifier(socket), 20*time.Millisecond)
+	watchdog.SetBusy(true)
+
+	watchdog.Run(ctx, func(err error) { assert.NoError(t, err) })
+
+	_ = conn.SetReadDeadline(time.Now().Add(10 * time.Millisecond))
+	_, err := conn.Read(make([]byte, 64))
+	assert.Error(t, err)
+}
+
+type failingNotifier struct {
+	calls    int
+	failures int
+}
+
+func (n *failingNotifier) Notify(states ...string) error {
+	n.calls++
+	if n.calls <= n.failures {
+		return errors.New("send failed")
+	}
+
+	return nil
+}
@@ -0,0 +1,183 @@
+package yddns
+
+import (
+	"github.com/drieschel/yddns/internal/cache"
+	"github.com/drieschel/yddns/internal/client"
+	"github.com/drieschel/yddns/internal/config"
+	"github.com/drieschel/yddns/internal/history"
+)
+
+type cacheAdapter struct {
+	cache Cache
+}
+
+func (a *cacheAdapter) Get(key string) (*cache.Item, error) {
+	item, err := a.cache.Get(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if item == nil {
+		return cache.NewItem(key, nil), nil
+	}
+
+	return (*cache.Item)(item), err
+}
+
+func (a *cacheAdapter) Set(item *cache.Item) error {
+	return a.cache.Set((*CacheItem)(item))
+}
+
+func (a *cacheAdapter) Delete(key string) error {
+	return a.cache.Delete(key)
+}
+
+func (a *cacheAdapter) DeleteExpired() error {
+	return a.cache.DeleteExpired()
+}
+
+func (a *cacheAdapter) IsValid(item cache.Item) bool {
+	return a.cache.IsValid(CacheItem(item))
+}
+
+type historyAdapter struct {
+	history History
+}
+
+func (a *historyAdapter) Add(entry *history.Entry) error {
+	return a.history.Add((*HistoryEntry)(entry))
+}
+
+func (a *historyAdapter) Find(filter history.Filter) ([]*history.Entry, error) {
+	entries, err := a.history.Find(HistoryFilter(filter))
+
+	var historyEntries []*history.Entry
+	for _, entry := range entries {
+		historyEntries = append(historyEntries, (*history.Entry)(entry))
+	}
+
+	return historyEntries, err
+}
+
+func (a *historyAdapter) LastUpdated(domain string, family string) (*history.Entry, error) {
+	entry, err := a.history.LastUpdated(domain, family)
+	if entry == nil {
+		return nil, err
+	}
+
+	return (*history.Entry)(entry), err
+}
+
+func (a *historyAdapter) Prune() error {
+	return a.history.Prune()
+}
+
+func newDomain(domain *config.Domain) *Domain {
+	return &Domain{
+		AddressPolicy:      domain.AddressPolicy,
+		AuthMethod:         domain.AuthMethod,
+		DomainName:         domain.DomainName,
+		Families:           domain.Families,
+		Host:               domain.Host,
+		Ip4Address:         domain.Ip4Address,
+		Ip6Address:         domain.Ip6Address,
+		Ip6HostId:          domain.Ip6HostId,
+		Ip6Interface:       domain.Ip6Interface,
+		Ip6InterfacePrefer: domain.Ip6InterfacePrefer,
+		Ip6InterfaceSuffix: domain.Ip6InterfaceSuffix,
+		Ip6Mac:             domain.Ip6Mac,
+		Ip6NeighborLookup:  domain.Ip6NeighborLookup,
+		Ip6Prefix:          domain.Ip6Prefix,
+		Ip6PrefixLength:    domain.Ip6PrefixLength,
+		Password:           domain.AuthPassword,
+		Protocol:           domain.Protocol,
+		RefreshUrl:         domain.RefreshUrl,
+		RefreshUrlIp4:      domain.RefreshUrlIp4,
+		RefreshUrlIp6:      domain.RefreshUrlIp6,
+		RequestMethod:      domain.RequestMethod,
+		Resolver:           domain.Resolver,
+		SkipIfResolved:     domain.SkipIfResolved,
+		Source:             domain.Source,
+		UserAgent:          domain.UserAgent,
+		Username:           domain.AuthUser,
+		Verify:             domain.Verify,
+		VerifyTimeout:      domain.VerifyTimeout,
+	}
+}
+
+func toConfigDomain(domain *Domain) *config.Domain {
+	return &config.Domain{
+		Template: config.Template{
+			AuthMethod:    domain.AuthMethod,
+			Host:          domain.Host,
+			Protocol:      domain.Protocol,
+			RefreshUrl:    domain.RefreshUrl,
+			RefreshUrlIp4: domain.RefreshUrlIp4,
+			RefreshUrlIp6: domain.RefreshUrlIp6,
+			RequestMethod: domain.RequestMethod,
+			UserAgent:     domain.UserAgent,
+		},
+		AddressPolicy:      domain.AddressPolicy,
+		AuthUser:           domain.Username,
+		AuthPassword:       domain.Password,
+		DomainName:         domain.DomainName,
+		Families:           domain.Families,
+		Ip4Address:         domain.Ip4Address,
+		Ip6Address:         domain.Ip6Address,
+		Ip6HostId:          domain.Ip6HostId,
+		Ip6Interface:       domain.Ip6Interface,
+		Ip6InterfacePrefer: domain.Ip6InterfacePrefer,
+		Ip6InterfaceSuffix: domain.Ip6InterfaceSuffix,
+		Ip6Mac:             domain.Ip6Mac,
+		Ip6NeighborLookup:  domain.Ip6NeighborLookup,
+		Ip6Prefix:          domain.Ip6Prefix,
+		Ip6PrefixLength:    domain.Ip6PrefixLength,
+		Resolver:           domain.Resolver,
+		SkipIfResolved:     domain.SkipIfResolved,
+		Source:             domain.Source,
+		Verify:             domain.Verify,
+		VerifyTimeout:      domain.VerifyTimeout,
+	}
+}
+
+func newResult(result *client.RefreshResult) *Result {
+	if result == nil {
+		return nil
+	}
+
+	var families []*Result
+	for _, familyResult := range result.Families {
+		families = append(families, newResult(familyResult))
+	}
+
+	return &Result{
+		Domain:     result.Domain,
+		Family:     result.Family,
+		Action:     result.Action,
+		Reason:     result.Reason,
+		Addresses:  result.Addresses,
+		HttpStatus: result.HttpStatus,
+		Body:       result.Body,
+		Duration:   result.Duration,
+		Families:   families,
+	}
+}
+
+func toRefreshResult(result *Result) *client.RefreshResult {
+	var families []*client.RefreshResult
+	for _, familyResult := range result.Families {
+		families = append(families, toRefreshResult(familyResult))
+	}
+
+	return &client.RefreshResult{
+		Domain:     result.Domain,
+		Family:     result.Family,
+		Action:     result.Action,
+		Reason:     result.Reason,
+		Addresses:  result.Addresses,
+		HttpStatus: result.HttpStatus,
+		Body:       result.Body,
+		Duration:   result.Duration,
+		Families:   families,
+	}
+}
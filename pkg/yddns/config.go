@@ -0,0 +1,153 @@
+package yddns
+
+import (
+	"github.com/drieschel/yddns/internal/config"
+)
+
+const TemplateSourceOption = "option"
+
+type ConfigOption func(c *Config) error
+
+func WithConfigFile(filePath string) ConfigOption {
+	return func(c *Config) error {
+		return c.config.ReadFile(filePath)
+	}
+}
+
+func WithDomains(domains ...*Domain) ConfigOption {
+	return func(c *Config) error {
+		var configDomains []*config.Domain
+		for _, domain := range domains {
+			configDomains = append(configDomains, toConfigDomain(domain))
+		}
+
+		return c.config.AddDomains(configDomains...)
+	}
+}
+
+func WithTemplate(name string, template *Template) ConfigOption {
+	return func(c *Config) error {
+		configTemplate := config.Template(*template)
+		c.config.AddTemplate(name, &configTemplate, TemplateSourceOption)
+
+		return nil
+	}
+}
+
+func WithRefreshInterval(seconds int) ConfigOption {
+	return func(c *Config) error {
+		c.config.RefreshInterval = seconds
+
+		return nil
+	}
+}
+
+func WithCacheExpirySeconds(createdExpiry int, modifiedExpiry int) ConfigOption {
+	return func(c *Config) error {
+		c.SetCacheExpirySeconds(createdExpiry, modifiedExpiry)
+
+		return nil
+	}
+}
+
+func WithCacheDir(dir string) ConfigOption {
+	return func(c *Config) error {
+		c.config.CacheDir = dir
+
+		return nil
+	}
+}
+
+func WithStateDir(dir string) ConfigOption {
+	return func(c *Config) error {
+		c.config.StateDir = dir
+
+		return nil
+	}
+}
+
+type Config struct {
+	config *config.Config
+}
+
+func NewConfig(appVersion string, opts ...ConfigOption) (*Config, error) {
+	cfg, err := config.NewConfig(appVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	return newConfig(cfg, opts)
+}
+
+func LoadConfig(appVersion string, opts ...ConfigOption) (*Config, error) {
+	cfg, err := config.NewFileConfig(appVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	return newConfig(cfg, opts)
+}
+
+func LoadOptionalConfig(appVersion string, opts ...ConfigOption) (*Config, error) {
+	cfg, err := config.NewOptionalFileConfig(appVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	return newConfig(cfg, opts)
+}
+
+func newConfig(cfg *config.Config, opts []ConfigOption) (*Config, error) {
+	c := &Config{config: cfg}
+	for _, opt := range opts {
+		err := opt(c)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+func (c *Config) CacheExpirySeconds() (int, int) {
+	return c.config.CacheCreatedExpirySeconds, c.config.CacheModifiedExpirySeconds
+}
+
+func (c *Config) SetCacheExpirySeconds(createdExpiry int, modifiedExpiry int) {
+	c.config.CacheCreatedExpirySeconds = createdExpiry
+	c.config.CacheModifiedExpirySeconds = modifiedExpiry
+}
+
+func (c *Config) RefreshInterval() int {
+	return c.config.RefreshInterval
+}
+
+func (c *Config) File() string {
+	return c.config.File
+}
+
+func (c *Config) Domains() ([]*Domain, error) {
+	domains, err := c.config.PrepareAndGetDomains()
+	if err != nil {
+		return nil, err
+	}
+
+	var publicDomains []*Domain
+	for _, domain := range domains {
+		publicDomains = append(publicDomains, newDomain(domain))
+	}
+
+	return publicDomains, nil
+}
+
+func (c *Config) PrepareDomain(domain *Domain) error {
+	configDomain := toConfigDomain(domain)
+	err := c.config.PrepareDomain(configDomain)
+	if err != nil {
+		return err
+	}
+
+	*domain = *newDomain(configDomain)
+
+	return nil
+}
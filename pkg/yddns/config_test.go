@@ -0,0 +1,63 @@
+package yddns
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/drieschel/yddns/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewConfigWithOptions(t *testing.T) {
+	config.Dirs = []string{}
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "config.toml")
+	_ = os.WriteFile(filePath, []byte("[[domains]]\ndomain = \"file.example.org\"\nrefresh_url = \":dyn\""), 0600)
+
+	cfg, err := NewConfig("42",
+		WithConfigFile(filePath),
+		WithDomains(&Domain{DomainName: "option.example.org", RefreshUrl: ":dyn"}),
+		WithTemplate("dyn", &Template{Host: "dyn.example.org", RefreshUrl: "https://<host>/update?hostname=<domain>&ip=<ip4>"}),
+		WithRefreshInterval(300),
+		WithCacheExpirySeconds(3600, 900),
+		WithCacheDir(filepath.Join(dir, "cache")),
+		WithStateDir(filepath.Join(dir, "state")),
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, filePath, cfg.File())
+	assert.Equal(t, 300, cfg.RefreshInterval())
+
+	createdExpiry, modifiedExpiry := cfg.CacheExpirySeconds()
+	assert.Equal(t, 3600, createdExpiry)
+	assert.Equal(t, 900, modifiedExpiry)
+	assert.Equal(t, filepath.Join(dir, "cache"), cfg.config.CacheDir)
+	assert.Equal(t, filepath.Join(dir, "state"), cfg.config.StateDir)
+
+	domains, err := cfg.Domains()
+
+	assert.NoError(t, err)
+	assert.Len(t, domains, 2)
+	assert.Equal(t, "file.example.org", domains[0].DomainName)
+	assert.Equal(t, "https://<host>/update?hostname=<domain>&ip=<ip4>", domains[0].RefreshUrl)
+	assert.Equal(t, "option.example.org", domains[1].DomainName)
+	assert.Equal(t, "dyn.example.org", domains[1].Host)
+}
+
+func TestNewConfigWithDuplicateDomains(t *testing.T) {
+	config.Dirs = []string{}
+
+	_, err := NewConfig("42", WithDomains(&Domain{DomainName: "home.example.org"}, &Domain{DomainName: "home.example.org"}))
+
+	assert.Error(t, err)
+}
+
+func TestNewConfigWithMissingConfigFile(t *testing.T) {
+	config.Dirs = []string{}
+	filePath := filepath.Join(t.TempDir(), "config.toml")
+
+	_, err := NewConfig("42", WithConfigFile(filePath))
+
+	assert.Equal(t, &config.NotFoundError{File: filePath}, err)
+}
@@ -0,0 +1,259 @@
+package yddns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/drieschel/yddns/internal/cache"
+	"github.com/drieschel/yddns/internal/client"
+	"github.com/drieschel/yddns/internal/config"
+	"github.com/drieschel/yddns/internal/history"
+	"github.com/drieschel/yddns/internal/resolver"
+)
+
+type Option func(u *Updater)
+
+func WithCache(cache Cache) Option {
+	return func(u *Updater) {
+		u.cache = &cacheAdapter{cache: cache}
+	}
+}
+
+func WithEventHandler(handler EventHandler) Option {
+	return func(u *Updater) {
+		u.handlers = append(u.handlers, handler)
+	}
+}
+
+func WithFailFast(failFast bool) Option {
+	return func(u *Updater) {
+		u.failFast = failFast
+	}
+}
+
+func WithHistory(history History) Option {
+	return func(u *Updater) {
+		u.history = &historyAdapter{history: history}
+	}
+}
+
+func WithHttpClient(httpClient HttpClient) Option {
+	return func(u *Updater) {
+		u.httpClient = httpClient
+	}
+}
+
+func WithIpSource(ipSource IpSource) Option {
+	return func(u *Updater) {
+		u.ipSource = ipSource
+	}
+}
+
+func WithResolver(resolver Resolver) Option {
+	return func(u *Updater) {
+		u.resolver = resolver
+	}
+}
+
+type Updater struct {
+	cache      cache.Cache
+	client     *client.Client
+	config     *Config
+	failFast   bool
+	handlers   []EventHandler
+	history    history.History
+	httpClient HttpClient
+	ipSource   IpSource
+	resolver   Resolver
+}
+
+func New(appVersion string, opts ...Option) (*Updater, error) {
+	cfg, err := NewConfig(appVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewWithConfig(cfg, opts...), nil
+}
+
+func NewWithConfig(cfg *Config, opts ...Option) *Updater {
+	u := &Updater{config: cfg}
+	for _, opt := range opts {
+		opt(u)
+	}
+
+	if u.cache == nil {
+		u.cache = cfg.config.CreateFileCache()
+	}
+
+	if u.history == nil {
+		fileHistory := cfg.config.CreateFileHistory()
+		fileHistory.SetWarningHandler(func(warning error) {
+			u.emit(&Event{Type: EventWarning, Err: warning})
+		})
+
+		u.history = fileHistory
+	}
+
+	if u.httpClient == nil {
+		u.httpClient = &http.Client{}
+	}
+
+	if u.ipSource == nil {
+		u.ipSource = client.NewIdentIpSource(u.httpClient)
+	}
+
+	if u.resolver == nil {
+		u.resolver = resolver.NewDnsResolverWithDefaultValues()
+	}
+
+	u.client = client.NewClientWithIpSource(u.cache, u.history, u.resolver, u.httpClient, u.ipSource)
+	u.client.SetWarningHandler(func(domain *config.Domain, warning error) {
+		u.emit(&Event{Type: EventWarning, Domain: newDomain(domain), Err: warning})
+	})
+
+	return u
+}
+
+func (u *Updater) Config() *Config {
+	return u.config
+}
+
+func (u *Updater) Domains() ([]*Domain, error) {
+	return u.config.Domains()
+}
+
+func (u *Updater) BuildRequests(ctx context.Context, domain *Domain) ([]*Request, error) {
+	preparedDomain := toConfigDomain(domain)
+	err := u.config.config.PrepareDomain(preparedDomain)
+	if err != nil {
+		return nil, err
+	}
+
+	if !preparedDomain.HasFamilyRefreshUrls() {
+		request, err := u.buildRequest(ctx, preparedDomain)
+		if err != nil {
+			return nil, err
+		}
+
+		return []*Request{{Request: request}}, nil
+	}
+
+	var requests []*Request
+	var errs []error
+	for _, familyDomain := range preparedDomain.SplitByFamily() {
+		family := familyDomain.Families[0]
+		request, err := u.buildRequest(ctx, familyDomain)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", family, err))
+			continue
+		}
+
+		requests = append(requests, &Request{Request: request, Family: family})
+	}
+
+	return requests, errors.Join(errs...)
+}
+
+func (u *Updater) buildRequest(ctx context.Context, domain *config.Domain) (*http.Request, error) {
+	refreshUrl, err := u.client.BuildRefreshUrl(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.client.BuildRefreshRequest(ctx, domain, refreshUrl)
+}
+
+func (u *Updater) Refresh(ctx context.Context, domain *Domain) (*Result, error) {
+	u.emit(&Event{Type: EventRefreshStarted, Domain: domain})
+
+	preparedDomain := toConfigDomain(domain)
+	err := u.config.config.PrepareDomain(preparedDomain)
+	if err != nil {
+		result := &Result{Domain: domain.DomainName, Action: ActionFailed, Reason: err.Error()}
+		u.emit(&Event{Type: EventRefreshFailed, Domain: domain, Result: result, Err: err})
+
+		return result, err
+	}
+
+	refreshResult, err := u.client.Refresh(ctx, preparedDomain)
+	result := newResult(refreshResult)
+	switch {
+	case err != nil:
+		u.emit(&Event{Type: EventRefreshFailed, Domain: domain, Result: result, Err: err})
+	case result.Action == ActionUpdated:
+		u.emit(&Event{Type: EventRefreshUpdated, Domain: domain, Result: result})
+	default:
+		u.emit(&Event{Type: EventRefreshSkipped, Domain: domain, Result: result})
+	}
+
+	return result, err
+}
+
+func (u *Updater) RefreshAll(ctx context.Context) ([]*Result, error) {
+	domains, err := u.Domains()
+	if err != nil {
+		return nil, err
+	}
+
+	u.client.Clear()
+
+	var results []*Result
+	var errs []error
+	for _, domain := range domains {
+		if ctx.Err() != nil {
+			errs = append(errs, ctx.Err())
+			break
+		}
+
+		result, err := u.Refresh(ctx, domain)
+		results = append(results, result)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", domain.DomainName, err))
+			if u.failFast {
+				break
+			}
+		}
+	}
+
+	err = u.history.Prune()
+	if err != nil {
+		u.emit(&Event{Type: EventPruneFailed, Err: err})
+		errs = append(errs, fmt.Errorf("pruning the history failed: %w", err))
+	}
+
+	err = errors.Join(errs...)
+	u.emit(&Event{Type: EventRefreshAllFinished, Results: results, Err: err})
+
+	return results, err
+}
+
+func (u *Updater) Run(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("refresh interval %s is invalid", interval)
+	}
+
+	_, err := u.Domains()
+	if err != nil {
+		return err
+	}
+
+	for {
+		_, _ = u.RefreshAll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+func (u *Updater) emit(event *Event) {
+	for _, handler := range u.handlers {
+		handler(event)
+	}
+}
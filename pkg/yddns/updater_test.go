@@ -0,0 +1,203 @@
+package yddns
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/drieschel/yddns/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUpdater_Refresh(t *testing.T) {
+	cfg := &Config{config: &config.Config{Templates: map[string]*config.Template{"dyn": {Host: "dyn.example.org", RefreshUrl: "https://<host>/update?hostname=<domain>&ip=<ip4>"}}}}
+	domain := &Domain{DomainName: "home.example.org", Families: []string{"ip4"}, RefreshUrl: ":dyn"}
+
+	cacheMock := NewMockCache(t)
+	cacheMock.EXPECT().Get(mock.Anything).RunAndReturn(func(key string) (*CacheItem, error) {
+		return &CacheItem{Key: key}, nil
+	}).Once()
+	cacheMock.EXPECT().IsValid(mock.Anything).Return(false).Once()
+	cacheMock.EXPECT().Set(mock.Anything).Return(nil).Once()
+
+	historyMock := NewMockHistory(t)
+	historyMock.EXPECT().LastUpdated("home.example.org", "ip4").Return(nil, nil).Once()
+	historyMock.EXPECT().Add(mock.Anything).Return(nil).Once()
+
+	ipSourceMock := NewMockIpSource(t)
+	ipSourceMock.EXPECT().Address(mock.Anything, "ip4").Return("125.148.255.41", nil).Once()
+
+	httpClientMock := NewMockHttpClient(t)
+	httpClientMock.EXPECT().Do(mock.MatchedBy(func(request *http.Request) bool {
+		return request.URL.String() == "https://dyn.example.org/update?hostname=home.example.org&ip=125.148.255.41"
+	})).Return(createHttpResponse("good"), nil).Once()
+
+	var events []string
+	updater := NewWithConfig(cfg, WithCache(cacheMock), WithHistory(historyMock), WithHttpClient(httpClientMock), WithIpSource(ipSourceMock), WithResolver(NewMockResolver(t)), WithEventHandler(func(event *Event) {
+		events = append(events, event.Type)
+	}))
+
+	result, err := updater.Refresh(context.Background(), domain)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &Result{Domain: "home.example.org", Action: ActionUpdated, Addresses: map[string]string{"ip4": "125.148.255.41"}, HttpStatus: 200, Body: "good", Duration: result.Duration}, result)
+	assert.Equal(t, []string{EventRefreshStarted, EventRefreshUpdated}, events)
+	assert.Equal(t, ":dyn", domain.RefreshUrl)
+}
+
+func TestUpdater_RefreshWithCacheMiss(t *testing.T) {
+	cfg := &Config{config: &config.Config{}}
+	domain := &Domain{DomainName: "home.example.org", Families: []string{"ip4"}, Ip4Address: "125.148.255.41", RefreshUrl: "https://dyn.example.org/update?ip=<ip4>"}
+
+	cacheMock := NewMockCache(t)
+	cacheMock.EXPECT().Get(mock.Anything).Return(nil, nil).Once()
+	cacheMock.EXPECT().IsValid(mock.MatchedBy(func(item CacheItem) bool {
+		return item.Key != "" && item.Value == nil
+	})).Return(false).Once()
+	cacheMock.EXPECT().Set(mock.Anything).Return(nil).Once()
+
+	historyMock := NewMockHistory(t)
+	historyMock.EXPECT().LastUpdated("home.example.org", "ip4").Return(nil, nil).Once()
+	historyMock.EXPECT().Add(mock.Anything).Return(nil).Once()
+
+	httpClientMock := NewMockHttpClient(t)
+	httpClientMock.EXPECT().Do(mock.Anything).Return(createHttpResponse("good"), nil).Once()
+
+	updater := NewWithConfig(cfg, WithCache(cacheMock), WithHistory(historyMock), WithHttpClient(httpClientMock), WithIpSource(NewMockIpSource(t)), WithResolver(NewMockResolver(t)))
+
+	result, err := updater.Refresh(context.Background(), domain)
+
+	assert.NoError(t, err)
+	assert.Equal(t, ActionUpdated, result.Action)
+}
+
+func TestUpdater_RefreshEmitsWarning(t *testing.T) {
+	cfg := &Config{config: &config.Config{}}
+	domain := &Domain{DomainName: "home.example.org", Families: []string{"ip4"}, Ip4Address: "10.0.0.2", RefreshUrl: "https://dyn.example.org/update?ip=<ip4>"}
+
+	cacheMock := NewMockCache(t)
+	cacheMock.EXPECT().Get(mock.Anything).Return(&CacheItem{}, nil).Once()
+	cacheMock.EXPECT().IsValid(mock.Anything).Return(false).Once()
+	cacheMock.EXPECT().Set(mock.Anything).Return(nil).Once()
+
+	historyMock := NewMockHistory(t)
+	historyMock.EXPECT().LastUpdated("home.example.org", "ip4").Return(nil, nil).Once()
+	historyMock.EXPECT().Add(mock.Anything).Return(nil).Once()
+
+	httpClientMock := NewMockHttpClient(t)
+	httpClientMock.EXPECT().Do(mock.Anything).Return(createHttpResponse("good"), nil).Once()
+
+	var warnings []*Event
+	updater := NewWithConfig(cfg, WithCache(cacheMock), WithHistory(historyMock), WithHttpClient(httpClientMock), WithIpSource(NewMockIpSource(t)), WithResolver(NewMockResolver(t)), WithEventHandler(func(event *Event) {
+		if event.Type == EventWarning {
+			warnings = append(warnings, event)
+		}
+	}))
+
+	_, err := updater.Refresh(context.Background(), domain)
+
+	assert.NoError(t, err)
+	assert.Len(t, warnings, 1)
+	assert.Equal(t, "home.example.org", warnings[0].Domain.DomainName)
+	assert.ErrorContains(t, warnings[0].Err, "ip4 address of domain \"home.example.org\": address 10.0.0.2 is a private address")
+}
+
+func TestUpdater_RefreshAll(t *testing.T) {
+	cfg := &Config{config: &config.Config{Domains: []*config.Domain{
+		{DomainName: "first.example.org", Ip4Address: "125.148.255.41", Families: []string{"ip4"}, Template: config.Template{RefreshUrl: "https://dyn.example.org/update?ip=<ip4>"}},
+		{DomainName: "second.example.org", Families: []string{"ip4"}, Template: config.Template{RefreshUrl: "https://dyn.example.org/update?ip=<ip4>"}},
+	}}}
+
+	cacheMock := NewMockCache(t)
+	cacheMock.EXPECT().Get(mock.Anything).RunAndReturn(func(key string) (*CacheItem, error) {
+		return &CacheItem{Key: key}, nil
+	}).Twice()
+	cacheMock.EXPECT().IsValid(mock.Anything).Return(true).Once()
+	cacheMock.EXPECT().IsValid(mock.Anything).Return(false).Once()
+	cacheMock.EXPECT().Set(mock.Anything).Return(nil).Once()
+
+	historyMock := NewMockHistory(t)
+	historyMock.EXPECT().Prune().Return(errors.New("read-only file system")).Once()
+
+	ipSourceMock := NewMockIpSource(t)
+	ipSourceMock.EXPECT().Address(mock.Anything, "ip4").Return("", errors.New("network is unreachable")).Once()
+
+	var events []string
+	updater := NewWithConfig(cfg, WithCache(cacheMock), WithHistory(historyMock), WithHttpClient(NewMockHttpClient(t)), WithIpSource(ipSourceMock), WithResolver(NewMockResolver(t)), WithEventHandler(func(event *Event) {
+		events = append(events, event.Type)
+	}))
+
+	results, err := updater.RefreshAll(context.Background())
+
+	assert.Len(t, results, 2)
+	assert.Equal(t, ActionSkipped, results[0].Action)
+	assert.Equal(t, ActionFailed, results[1].Action)
+	assert.ErrorContains(t, err, "second.example.org: ip4 address could not be determined: network is unreachable")
+	assert.ErrorContains(t, err, "pruning the history failed: read-only file system")
+	assert.Equal(t, []string{EventRefreshStarted, EventRefreshSkipped, EventRefreshStarted, EventRefreshFailed, EventPruneFailed, EventRefreshAllFinished}, events)
+}
+
+func TestUpdater_RefreshAllWithFailFast(t *testing.T) {
+	cfg := &Config{config: &config.Config{Domains: []*config.Domain{
+		{DomainName: "first.example.org", Families: []string{"ip4"}, Template: config.Template{RefreshUrl: "https://dyn.example.org/update?ip=<ip4>"}},
+		{DomainName: "second.example.org", Ip4Address: "125.148.255.41", Families: []string{"ip4"}, Template: config.Template{RefreshUrl: "https://dyn.example.org/update?ip=<ip4>"}},
+	}}}
+
+	cacheMock := NewMockCache(t)
+	cacheMock.EXPECT().Get(mock.Anything).RunAndReturn(func(key string) (*CacheItem, error) {
+		return &CacheItem{Key: key}, nil
+	}).Once()
+	cacheMock.EXPECT().IsValid(mock.Anything).Return(false).Once()
+
+	historyMock := NewMockHistory(t)
+	historyMock.EXPECT().Prune().Return(nil).Once()
+
+	ipSourceMock := NewMockIpSource(t)
+	ipSourceMock.EXPECT().Address(mock.Anything, "ip4").Return("", errors.New("network is unreachable")).Once()
+
+	updater := NewWithConfig(cfg, WithCache(cacheMock), WithHistory(historyMock), WithHttpClient(NewMockHttpClient(t)), WithIpSource(ipSourceMock), WithResolver(NewMockResolver(t)), WithFailFast(true))
+
+	results, err := updater.RefreshAll(context.Background())
+
+	assert.Len(t, results, 1)
+	assert.Equal(t, ActionFailed, results[0].Action)
+	assert.EqualError(t, err, "first.example.org: ip4 address could not be determined: network is unreachable")
+}
+
+func TestUpdater_BuildRequests(t *testing.T) {
+	cfg := &Config{config: &config.Config{}}
+	domain := &Domain{DomainName: "home.example.org", Ip4Address: "125.148.255.41", Ip6Address: "2001:db8::1", Password: "secret", RefreshUrlIp4: "https://dyn.example.org/update?ip=<ip4>", RefreshUrlIp6: "https://dyn.example.org/update?ip=<ip6>"}
+
+	updater := NewWithConfig(cfg, WithCache(NewMockCache(t)), WithHistory(NewMockHistory(t)), WithHttpClient(NewMockHttpClient(t)), WithIpSource(NewMockIpSource(t)), WithResolver(NewMockResolver(t)))
+
+	requests, err := updater.BuildRequests(context.Background(), domain)
+
+	assert.NoError(t, err)
+	assert.Len(t, requests, 2)
+	assert.Equal(t, "ip4", requests[0].Family)
+	assert.Equal(t, "https://dyn.example.org/update?ip=125.148.255.41", requests[0].URL.String())
+	assert.Equal(t, "ip6", requests[1].Family)
+	assert.Equal(t, "https://dyn.example.org/update?ip=2001:db8::1", requests[1].URL.String())
+	assert.Equal(t, "https://dyn.example.org/update?ip=<ip4>", domain.RefreshUrlIp4)
+}
+
+func TestUpdater_RunWithInvalidInterval(t *testing.T) {
+	updater := NewWithConfig(&Config{config: &config.Config{}}, WithCache(NewMockCache(t)), WithHistory(NewMockHistory(t)))
+
+	err := updater.Run(context.Background(), 0)
+
+	assert.EqualError(t, err, "refresh interval 0s is invalid")
+}
+
+func createHttpResponse(responseBody string) *http.Response {
+	return &http.Response{
+		Status:     "OK",
+		StatusCode: 200,
+		Header:     make(http.Header),
+		Body:       io.NopCloser(bytes.NewBufferString(responseBody)),
+	}
+}
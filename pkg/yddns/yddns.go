@@ -0,0 +1,157 @@
+// Package yddns refreshes dynamic dns domains and is the base of the yddns cli.
+package yddns
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+const (
+	ActionFailed  = "failed"
+	ActionSkipped = "skipped"
+	ActionUpdated = "updated"
+
+	EventRefreshStarted     = "refresh_started"
+	EventRefreshUpdated     = "refresh_updated"
+	EventRefreshSkipped     = "refresh_skipped"
+	EventRefreshFailed      = "refresh_failed"
+	EventRefreshAllFinished = "refresh_all_finished"
+	EventPruneFailed        = "prune_failed"
+	EventWarning            = "warning"
+)
+
+type Cache interface {
+	Get(key string) (*CacheItem, error)
+	Set(item *CacheItem) error
+	Delete(key string) error
+	DeleteExpired() error
+	IsValid(item CacheItem) bool
+}
+
+type CacheItem struct {
+	Key            string
+	Value          interface{}
+	Created        *time.Time
+	CreatedExpiry  int
+	Modified       *time.Time
+	ModifiedExpiry int
+}
+
+type History interface {
+	Add(entry *HistoryEntry) error
+	Find(filter HistoryFilter) ([]*HistoryEntry, error)
+	LastUpdated(domain string, family string) (*HistoryEntry, error)
+	Prune() error
+}
+
+type HistoryEntry struct {
+	Time       time.Time `json:"time"`
+	Domain     string    `json:"domain"`
+	Family     string    `json:"family"`
+	OldAddress string    `json:"old_address"`
+	NewAddress string    `json:"new_address"`
+	Result     string    `json:"result"`
+	Message    string    `json:"message"`
+}
+
+type HistoryFilter struct {
+	Domain string
+	Family string
+	Since  time.Time
+	Until  time.Time
+}
+
+type HttpClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+type IpSource interface {
+	Address(ctx context.Context, family string) (string, error)
+}
+
+type Resolver interface {
+	Lookup(ctx context.Context, server string, domain string, family string) ([]string, error)
+}
+
+type Domain struct {
+	AddressPolicy      string
+	AuthMethod         string
+	DomainName         string
+	Families           []string
+	Host               string
+	Ip4Address         string
+	Ip6Address         string
+	Ip6HostId          string
+	Ip6Interface       string
+	Ip6InterfacePrefer string
+	Ip6InterfaceSuffix string
+	Ip6Mac             string
+	Ip6NeighborLookup  bool
+	Ip6Prefix          string
+	Ip6PrefixLength    int
+	Password           string
+	Protocol           string
+	RefreshUrl         string
+	RefreshUrlIp4      string
+	RefreshUrlIp6      string
+	RequestMethod      string
+	Resolver           string
+	SkipIfResolved     bool
+	Source             string
+	UserAgent          string
+	Username           string
+	Verify             bool
+	VerifyTimeout      int
+}
+
+type Template struct {
+	AuthMethod    string
+	Extends       string
+	Host          string
+	Protocol      string
+	RefreshUrl    string
+	RefreshUrlIp4 string
+	RefreshUrlIp6 string
+	RequestMethod string
+	UserAgent     string
+}
+
+type Result struct {
+	Domain     string            `json:"domain"`
+	Family     string            `json:"family,omitempty"`
+	Action     string            `json:"action"`
+	Reason     string            `json:"reason,omitempty"`
+	Addresses  map[string]string `json:"addresses,omitempty"`
+	HttpStatus int               `json:"http_status,omitempty"`
+	Body       string            `json:"body,omitempty"`
+	Duration   time.Duration     `json:"-"`
+	Families   []*Result         `json:"families,omitempty"`
+}
+
+func (r *Result) MarshalJSON() ([]byte, error) {
+	return toRefreshResult(r).MarshalJSON()
+}
+
+func (r *Result) HasFailed() bool {
+	return toRefreshResult(r).HasFailed()
+}
+
+func (r *Result) String() string {
+	return toRefreshResult(r).String()
+}
+
+type Request struct {
+	*http.Request
+	Family string
+}
+
+type Event struct {
+	Type    string
+	Domain  *Domain
+	Result  *Result
+	Results []*Result
+	Err     error
+}
+
+type EventHandler func(event *Event)